@@ -0,0 +1,138 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import "cmp"
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint that permits any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// Sum returns the sum of the elements in the Query.
+//
+// The sum of an empty Query is zero.
+func Sum[E Number](q *Query[E]) E {
+	return SumBy(q, identity[E])
+}
+
+// SumBy returns the sum of the values returned from f for every element
+// in the Query.
+//
+// The sum of an empty Query, or with a nil f, is zero.
+func SumBy[E any, N Number](q *Query[E], f func(E) N) N {
+	var sum N
+	if f == nil {
+		return sum
+	}
+	for _, e := range *q {
+		sum += f(e)
+	}
+	return sum
+}
+
+// Average returns the arithmetic mean of the elements in the Query.
+//
+// The second result is false if the Query is empty.
+func Average[E Number](q *Query[E]) (float64, bool) {
+	return AverageBy(q, identity[E])
+}
+
+// AverageBy returns the arithmetic mean of the values returned from f
+// for every element in the Query.
+//
+// The second result is false if the Query is empty or f is nil.
+func AverageBy[E any, N Number](q *Query[E], f func(E) N) (float64, bool) {
+	if len(*q) < 1 || f == nil {
+		return 0, false
+	}
+	sum := 0.0
+	for _, e := range *q {
+		sum += float64(f(e))
+	}
+	return sum / float64(len(*q)), true
+}
+
+// Min returns the smallest element in the Query.
+//
+// The second result is false if the Query is empty. For floating-point
+// elements, a NaN element makes the result NaN, as with the built-in min.
+func Min[E cmp.Ordered](q *Query[E]) (E, bool) {
+	return reduceOrdered(q, func(a, b E) E {
+		return min(a, b)
+	})
+}
+
+// Max returns the largest element in the Query.
+//
+// The second result is false if the Query is empty. For floating-point
+// elements, a NaN element makes the result NaN, as with the built-in max.
+func Max[E cmp.Ordered](q *Query[E]) (E, bool) {
+	return reduceOrdered(q, func(a, b E) E {
+		return max(a, b)
+	})
+}
+
+// reduceOrdered combines the elements of q with f, starting from the
+// first element. The second result is false if q is empty.
+func reduceOrdered[E cmp.Ordered](q *Query[E], f func(E, E) E) (E, bool) {
+	var result E
+	if len(*q) < 1 {
+		return result, false
+	}
+	result = (*q)[0]
+	for _, e := range (*q)[1:] {
+		result = f(result, e)
+	}
+	return result, true
+}
+
+// MinBy returns the element of the Query with the smallest key,
+// as computed by key.
+//
+// If several elements share the smallest key, the first one is returned.
+// The second result is false if the Query is empty or key is nil.
+func MinBy[E any, K cmp.Ordered](q *Query[E], key func(E) K) (E, bool) {
+	return extremeBy(q, key, cmp.Less[K])
+}
+
+// MaxBy returns the element of the Query with the largest key,
+// as computed by key.
+//
+// If several elements share the largest key, the first one is returned.
+// The second result is false if the Query is empty or key is nil.
+func MaxBy[E any, K cmp.Ordered](q *Query[E], key func(E) K) (E, bool) {
+	return extremeBy(q, key, func(a, b K) bool {
+		return cmp.Less(b, a)
+	})
+}
+
+// extremeBy returns the first element of q whose key is not beaten by
+// any other key according to better.
+func extremeBy[E any, K cmp.Ordered](q *Query[E], key func(E) K, better func(a, b K) bool) (E, bool) {
+	var result E
+	if len(*q) < 1 || key == nil {
+		return result, false
+	}
+	result = (*q)[0]
+	best := key(result)
+	for _, e := range (*q)[1:] {
+		if k := key(e); better(k, best) {
+			result, best = e, k
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_Sum(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query[int]
+		want int
+	}{
+		{
+			name: "empty slice",
+			q:    &Query[int]{},
+			want: 0,
+		},
+		{
+			name: "non-empty slice",
+			q:    &Query[int]{1, 2, 3, 4},
+			want: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sum(tt.q); got != tt.want {
+				t.Errorf("Sum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SumBy(t *testing.T) {
+	q := &Query[Person]{{"Bob", 31}, {"Jenny", 26}}
+	if got := SumBy(q, func(p Person) float64 { return float64(p.Age) / 2 }); got != 28.5 {
+		t.Errorf("SumBy() = %v, want %v", got, 28.5)
+	}
+	if got := SumBy[Person, int](q, nil); got != 0 {
+		t.Errorf("SumBy(nil) = %v, want %v", got, 0)
+	}
+}
+
+func Test_Average(t *testing.T) {
+	tests := []struct {
+		name   string
+		q      *Query[int]
+		want   float64
+		wantOk bool
+	}{
+		{
+			name:   "empty slice",
+			q:      &Query[int]{},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name:   "non-empty slice",
+			q:      &Query[int]{1, 2, 3, 4},
+			want:   2.5,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Average(tt.q)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Average() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_AverageBy(t *testing.T) {
+	q := &Query[Person]{{"Bob", 31}, {"Jenny", 26}, {"John", 42}}
+	got, ok := AverageBy(q, func(p Person) int { return p.Age })
+	if got != 33 || !ok {
+		t.Errorf("AverageBy() = %v, %v, want %v, %v", got, ok, 33, true)
+	}
+}
+
+func Test_MinMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       *Query[float64]
+		wantMin float64
+		wantMax float64
+		wantOk  bool
+	}{
+		{
+			name:   "empty slice",
+			q:      &Query[float64]{},
+			wantOk: false,
+		},
+		{
+			name:    "single element",
+			q:       &Query[float64]{1.5},
+			wantMin: 1.5,
+			wantMax: 1.5,
+			wantOk:  true,
+		},
+		{
+			name:    "non-empty slice",
+			q:       &Query[float64]{3, -1, 7, 2},
+			wantMin: -1,
+			wantMax: 7,
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := Min(tt.q); got != tt.wantMin || ok != tt.wantOk {
+				t.Errorf("Min() = %v, %v, want %v, %v", got, ok, tt.wantMin, tt.wantOk)
+			}
+			if got, ok := Max(tt.q); got != tt.wantMax || ok != tt.wantOk {
+				t.Errorf("Max() = %v, %v, want %v, %v", got, ok, tt.wantMax, tt.wantOk)
+			}
+		})
+	}
+	if got, _ := Min(&Query[float64]{1, math.NaN()}); !math.IsNaN(got) {
+		t.Errorf("Min() = %v, want NaN", got)
+	}
+}
+
+func Test_MinByMaxBy(t *testing.T) {
+	q := &Query[Person]{{"Bob", 31}, {"Jenny", 17}, {"John", 42}, {"Michael", 17}, {"Ann", 42}}
+	age := func(p Person) int { return p.Age }
+	if got, ok := MinBy(q, age); got.Name != "Jenny" || !ok {
+		t.Errorf("MinBy() = %v, %v, want %v, %v", got, ok, "Jenny", true)
+	}
+	if got, ok := MaxBy(q, age); got.Name != "John" || !ok {
+		t.Errorf("MaxBy() = %v, %v, want %v, %v", got, ok, "John", true)
+	}
+	if _, ok := MinBy(&Query[Person]{}, age); ok {
+		t.Errorf("MinBy() on empty slice = %v, want false", ok)
+	}
+	if _, ok := MaxBy[Person, int](q, nil); ok {
+		t.Errorf("MaxBy(nil) = %v, want false", ok)
+	}
+}
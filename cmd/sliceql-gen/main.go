@@ -0,0 +1,503 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+// Sliceql-gen generates typed query helpers for struct types, so that
+// they can be queried with the sliceql package without reflection.
+//
+// Given the name of a struct type T, it writes a Go source file to the
+// package of T that declares
+//
+//   - TFields, a struct with a sliceql.Field descriptor for every
+//     exported field of T with an ordered type, including the fields
+//     promoted from embedded structs, which read as the zero value
+//     through a nil embedded pointer. A descriptor provides the typed
+//     accessor of the field (Get), which is also a key for
+//     sliceql.OrderBy, the comparators Less for Query.Sort and Compare
+//     for sliceql.OrderByFunc, and the predicate builders Eq, Ne, Lt,
+//     Le, Gt, Ge, In and Between for Query.Where. Since sliceql.Field
+//     requires a cmp.Ordered type, fields of other types, bool fields
+//     among them, get no descriptor; they can still be tested with a
+//     plain function in Query.Where, or by name in the query language;
+//   - EqualT, a function that reports whether two values of T are equal,
+//     to be used with Query.Equal. Fields are compared with ==, except
+//     for slices and maps, which are compared element by element with
+//     slices.Equal and maps.Equal. Fields that cannot be compared
+//     without the risk of a run-time panic, such as functions and
+//     interfaces, or slices and maps of them, are left out.
+//
+// Fields are named like in the query language: by their sliceql tag,
+// their json tag or their Go name. Fields of embedded structs are
+// promoted like in encoding/json. The descriptor of a promoted field
+// whose Go name is taken by a shallower field is named after its path,
+// such as BaseID for Base.ID.
+//
+// Usage:
+//
+//	sliceql-gen -type T[,U...] [-output file] [package]
+//
+// It is typically run by go generate:
+//
+//	//go:generate go run github.com/dmundt/sliceql/cmd/sliceql-gen -type Person
+//
+// The default output file is t_sliceql.go, after the lower-cased name of
+// the first type, in the directory of the package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/types"
+	"log"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sliceqlPath is the import path of the sliceql package.
+const sliceqlPath = "github.com/dmundt/sliceql"
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output    = flag.String("output", "", "output file name; default <dir>/<type>_sliceql.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of sliceql-gen:\n")
+	fmt.Fprintf(os.Stderr, "\tsliceql-gen -type T[,U...] [-output file] [package]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sliceql-gen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	names := strings.Split(*typeNames, ",")
+	pattern := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		pattern = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkg, err := load(pattern)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(pkg, names)
+	if err != nil {
+		log.Fatal(err)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(filepath.Dir(pkg.GoFiles[0]), strings.ToLower(names[0])+"_sliceql.go")
+	}
+	if err := os.WriteFile(name, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load loads the single package matched by pattern.
+func load(pattern string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+	}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%s matches %d packages, want 1", pattern, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+	if len(pkg.GoFiles) == 0 {
+		return nil, fmt.Errorf("package %s has no Go files", pkg.PkgPath)
+	}
+	return pkg, nil
+}
+
+// generator writes the source of the helpers of a package.
+type generator struct {
+	buf bytes.Buffer
+	pkg *types.Package
+	// imports maps the paths of the imported packages to their names.
+	imports map[string]string
+}
+
+// generate returns the formatted source of the helpers of the struct
+// types called names in pkg.
+func generate(pkg *packages.Package, names []string) ([]byte, error) {
+	g := &generator{pkg: pkg.Types, imports: make(map[string]string)}
+	for _, name := range names {
+		obj := pkg.Types.Scope().Lookup(name)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s not found in package %s", name, pkg.PkgPath)
+		}
+		tn, ok := obj.(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a type", name)
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("%s is not a named non-generic type", name)
+		}
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct type", name)
+		}
+		g.fields(name, st)
+		g.equal(name, st)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"sliceql-gen -type %s\"; DO NOT EDIT.\n\n", strings.Join(names, ","))
+	fmt.Fprintf(&b, "package %s\n\n", pkg.Name)
+	if len(g.imports) > 0 {
+		b.WriteString("import (\n")
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		for _, path := range paths {
+			if name := g.imports[path]; name != pathpkg.Base(path) {
+				fmt.Fprintf(&b, "\t%s %q\n", name, path)
+			} else {
+				fmt.Fprintf(&b, "\t%q\n", path)
+			}
+		}
+		b.WriteString(")\n")
+	}
+	b.Write(g.buf.Bytes())
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// fields writes the variable with the field descriptors of the struct
+// type name.
+func (g *generator) fields(name string, st *types.Struct) {
+	var fields []*field
+	for _, f := range visibleFields(st) {
+		if ordered(f.typ) {
+			fields = append(fields, f)
+		}
+	}
+	// Promoted fields may share their Go name with shallower ones, so
+	// the descriptors of the deeper ones are named after their path.
+	byDepth := slices.Clone(fields)
+	slices.SortStableFunc(byDepth, func(a, b *field) int {
+		return len(a.index) - len(b.index)
+	})
+	used := make(map[string]bool)
+	for _, f := range byDepth {
+		for _, key := range []string{f.goName, strings.Join(f.path, "")} {
+			if !used[key] {
+				used[key] = true
+				f.key = key
+				break
+			}
+		}
+	}
+	fields = slices.DeleteFunc(fields, func(f *field) bool {
+		return f.key == ""
+	})
+
+	sliceql := g.qualify(sliceqlPath, "sliceql")
+	g.printf("\n// %sFields describes the fields of %s that can be queried.\n", name, name)
+	g.printf("var %sFields = struct {\n", name)
+	for _, f := range fields {
+		g.printf("%s %sField[%s, %s]\n", f.key, sliceql, name, g.typeString(f.typ))
+	}
+	g.printf("}{\n")
+	for _, f := range fields {
+		typ := g.typeString(f.typ)
+		g.printf("%s: %sField[%s, %s]{\n", f.key, sliceql, name, typ)
+		g.printf("Name: %q,\n", f.name)
+		sel := g.selector(st, f)
+		if len(f.ptrs) == 0 {
+			g.printf("Get: func(e %s) %s { return e.%s },\n", name, typ, sel)
+		} else {
+			// Like the query language, read the zero value through
+			// nil embedded pointers rather than panic.
+			conds := make([]string, len(f.ptrs))
+			for i, n := range f.ptrs {
+				conds[i] = "e." + strings.Join(f.path[:n], ".") + " != nil"
+			}
+			g.printf("Get: func(e %s) (v %s) {\n", name, typ)
+			g.printf("if %s {\n", strings.Join(conds, " && "))
+			g.printf("v = e.%s\n", sel)
+			g.printf("}\n")
+			g.printf("return v\n")
+			g.printf("},\n")
+		}
+		g.printf("},\n")
+	}
+	g.printf("}\n")
+}
+
+// A field is a field of a struct type that is visible in the query
+// language, declared by the struct itself or promoted from an embedded
+// struct.
+type field struct {
+	name, goName string
+	typ          types.Type
+	// index is the index sequence of the field, like that of
+	// reflect.StructField.
+	index []int
+	// path holds the Go names of the embedded fields leading to the
+	// field, followed by its own.
+	path []string
+	// ptrs holds the lengths of the prefixes of path that end at an
+	// embedded pointer.
+	ptrs []int
+	// key is the name of the descriptor of the field.
+	key string
+}
+
+// visibleFields returns the fields of st that are visible in the query
+// language, in declaration order. Like the schemas of the sliceql
+// package, the fields of embedded structs are promoted as in
+// encoding/json: a field of a shallower struct hides those of deeper
+// ones, fields of the same depth with the same name hide each other,
+// and an embedded struct with a tag name is a field of its own.
+func visibleFields(st *types.Struct) []*field {
+	type embedded struct {
+		st  *types.Struct
+		typ types.Type
+		f   *field
+	}
+	var fields []*field
+	byName := make(map[string]*field)
+	hidden := make(map[string]bool)
+	visited := make(map[types.Type]bool)
+	for level := []embedded{{st: st, f: &field{}}}; len(level) > 0; {
+		var next []embedded
+		names := make(map[string][]*field)
+		for _, e := range level {
+			if e.typ != nil {
+				if visited[e.typ] {
+					continue
+				}
+				visited[e.typ] = true
+			}
+			for i := 0; i < e.st.NumFields(); i++ {
+				v := e.st.Field(i)
+				name, ok := fieldName(e.st.Tag(i))
+				if !ok {
+					continue
+				}
+				f := &field{
+					name:   name,
+					goName: v.Name(),
+					typ:    v.Type(),
+					index:  append(slices.Clip(e.f.index), i),
+					path:   append(slices.Clip(e.f.path), v.Name()),
+					ptrs:   e.f.ptrs,
+				}
+				if v.Embedded() {
+					t, ptr := v.Type(), false
+					if p, ok := t.Underlying().(*types.Pointer); ok {
+						t, ptr = p.Elem(), true
+					}
+					if est, ok := t.Underlying().(*types.Struct); ok && name == "" {
+						if ptr {
+							f.ptrs = append(slices.Clip(f.ptrs), len(f.path))
+						}
+						next = append(next, embedded{st: est, typ: t, f: f})
+						continue
+					}
+				}
+				if !v.Exported() {
+					continue
+				}
+				if f.name == "" {
+					f.name = v.Name()
+				}
+				names[f.name] = append(names[f.name], f)
+			}
+		}
+		for name, fs := range names {
+			if _, ok := byName[name]; ok || hidden[name] {
+				continue
+			}
+			if len(fs) > 1 {
+				hidden[name] = true
+				continue
+			}
+			byName[name] = fs[0]
+			fields = append(fields, fs[0])
+		}
+		level = next
+	}
+	slices.SortFunc(fields, func(a, b *field) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return fields
+}
+
+// selector returns the selector of the field f of the struct st: its Go
+// name if Go promotes it to st, or else its full path.
+func (g *generator) selector(st *types.Struct, f *field) string {
+	obj, index, _ := types.LookupFieldOrMethod(st, false, g.pkg, f.goName)
+	if _, ok := obj.(*types.Var); ok && slices.Equal(index, f.index) {
+		return f.goName
+	}
+	return strings.Join(f.path, ".")
+}
+
+// equal writes the equality function of the struct type name.
+func (g *generator) equal(name string, st *types.Struct) {
+	var terms, skipped []string
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if f.Name() == "_" {
+			continue
+		}
+		switch t := f.Type().Underlying().(type) {
+		case *types.Slice:
+			if strictlyComparable(t.Elem()) {
+				terms = append(terms, fmt.Sprintf("%sEqual(a.%s, b.%s)", g.qualify("slices", "slices"), f.Name(), f.Name()))
+				continue
+			}
+		case *types.Map:
+			if strictlyComparable(t.Elem()) {
+				terms = append(terms, fmt.Sprintf("%sEqual(a.%s, b.%s)", g.qualify("maps", "maps"), f.Name(), f.Name()))
+				continue
+			}
+		default:
+			if strictlyComparable(f.Type()) {
+				terms = append(terms, fmt.Sprintf("a.%s == b.%s", f.Name(), f.Name()))
+				continue
+			}
+		}
+		skipped = append(skipped, f.Name())
+	}
+	if len(terms) == 0 {
+		terms = append(terms, "true")
+	}
+
+	g.printf("\n// Equal%s reports whether the %s values a and b are equal.\n", name, name)
+	g.printf("// It can be passed to Query.Equal.\n")
+	switch len(skipped) {
+	case 0:
+	case 1:
+		g.printf("//\n// The field %s is not compared.\n", skipped[0])
+	default:
+		g.printf("//\n// The fields %s are not compared.\n", strings.Join(skipped, ", "))
+	}
+	g.printf("func Equal%s(a, b %s) bool {\n", name, name)
+	g.printf("return %s\n", strings.Join(terms, " &&\n"))
+	g.printf("}\n")
+}
+
+// qualify returns the qualifier of the package with the given path and
+// name, and records its import. The qualifier of the generated package
+// itself is empty.
+func (g *generator) qualify(path, name string) string {
+	if path == g.pkg.Path() {
+		return ""
+	}
+	if n, ok := g.imports[path]; ok {
+		return n + "."
+	}
+	used := func(n string) bool {
+		if g.pkg.Scope().Lookup(n) != nil {
+			return true
+		}
+		for _, m := range g.imports {
+			if m == n {
+				return true
+			}
+		}
+		return false
+	}
+	n := name
+	for i := 2; used(n); i++ {
+		n = name + strconv.Itoa(i)
+	}
+	g.imports[path] = n
+	return n + "."
+}
+
+// typeString returns the type t as written in the generated package.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		return strings.TrimSuffix(g.qualify(p.Path(), p.Name()), ".")
+	})
+}
+
+// strictlyComparable reports whether values of type t can be compared
+// with == without a run-time panic. Unlike types.Comparable, it rejects
+// interfaces and types that contain them, whose dynamic values may not
+// be comparable.
+func strictlyComparable(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		return false
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if !strictlyComparable(u.Field(i).Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Array:
+		return strictlyComparable(u.Elem())
+	}
+	return types.Comparable(t)
+}
+
+// ordered reports whether t satisfies cmp.Ordered.
+func ordered(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsOrdered != 0
+}
+
+// fieldName returns the name of a field in the query language from its
+// struct tag: the name of its sliceql tag, or else of its json tag.
+// It returns ok false if the field is skipped.
+func fieldName(tag string) (name string, ok bool) {
+	st := reflect.StructTag(tag)
+	t, found := st.Lookup("sliceql")
+	if !found {
+		t = st.Get("json")
+	}
+	if t == "-" {
+		return "", false
+	}
+	name, _, _ = strings.Cut(t, ",")
+	if name == "" && found {
+		name, _, _ = strings.Cut(st.Get("json"), ",")
+		if name == "-" {
+			name = ""
+		}
+	}
+	return name, true
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func Test_generate(t *testing.T) {
+	pkg, err := load("./testdata/people")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	got, err := generate(pkg, []string{"Person", "Address"})
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	golden := filepath.Join("testdata", "people", "person_sliceql.go")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("generate() = %s, want %s", got, want)
+	}
+}
+
+func Test_generate_Errors(t *testing.T) {
+	pkg, err := load("./testdata/people")
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		typ  string
+	}{
+		{
+			name: "unknown type",
+			typ:  "Nobody",
+		},
+		{
+			name: "not a type",
+			typ:  "PersonFields",
+		},
+		{
+			name: "not a struct",
+			typ:  "Level",
+		},
+		{
+			name: "generic type",
+			typ:  "Box",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := generate(pkg, []string{tt.typ}); err == nil {
+				t.Errorf("generate(%s) error = nil, want error", tt.typ)
+			}
+		})
+	}
+}
+
+func Test_fieldName(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{``, "", true},
+		{`sliceql:"name"`, "name", true},
+		{`json:"name,omitempty"`, "name", true},
+		{`sliceql:"-"`, "", false},
+		{`json:"-"`, "", false},
+		{`sliceql:",omitempty" json:"mail"`, "mail", true},
+		{`sliceql:"id" json:"-"`, "id", true},
+	}
+	for _, tt := range tests {
+		got, ok := fieldName(tt.tag)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("fieldName(%q) = %q, %t, want %q, %t", tt.tag, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func Test_strictlyComparable(t *testing.T) {
+	anyType := types.Universe.Lookup("any").Type()
+	intType := types.Typ[types.Int]
+	field := func(typ types.Type) *types.Var {
+		return types.NewField(token.NoPos, nil, "F", typ, false)
+	}
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{"int", intType, true},
+		{"pointer", types.NewPointer(anyType), true},
+		{"any", anyType, false},
+		{"array of int", types.NewArray(intType, 2), true},
+		{"array of any", types.NewArray(anyType, 2), false},
+		{"struct of int", types.NewStruct([]*types.Var{field(intType)}, nil), true},
+		{"struct of any", types.NewStruct([]*types.Var{field(anyType)}, nil), false},
+		{"slice", types.NewSlice(intType), false},
+	}
+	for _, tt := range tests {
+		if got := strictlyComparable(tt.typ); got != tt.want {
+			t.Errorf("strictlyComparable(%s) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
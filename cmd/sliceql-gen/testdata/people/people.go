@@ -0,0 +1,53 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+// Package people is the input of the tests of sliceql-gen.
+package people
+
+import "time"
+
+//go:generate go run github.com/dmundt/sliceql/cmd/sliceql-gen -type Person,Address
+
+type Address struct {
+	Street string
+	City   string `json:"city"`
+}
+
+type Base struct {
+	ID int
+}
+
+// Audit is embedded through a pointer. Its Score is hidden by that of
+// Person, and its Name is promoted next to that of Person, which is
+// named by its tag.
+type Audit struct {
+	Created int64
+	Name    string
+	Score   float64
+}
+
+type Person struct {
+	Base
+	*Audit
+	Name     string `sliceql:"name"`
+	Age      int    `json:"age,omitempty"`
+	Score    float64
+	Active   bool
+	Joined   time.Duration
+	Secret   string `sliceql:"-"`
+	Address  *Address
+	Tags     []string
+	Attrs    map[string]int
+	Notify   func(string)
+	Extra    any
+	Values   []any
+	Pair     [2]any
+	nickname string
+}
+
+type Level int
+
+type Box[T any] struct {
+	Value T
+}
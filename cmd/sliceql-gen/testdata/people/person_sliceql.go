@@ -0,0 +1,101 @@
+// Code generated by "sliceql-gen -type Person,Address"; DO NOT EDIT.
+
+package people
+
+import (
+	"github.com/dmundt/sliceql"
+	"maps"
+	"slices"
+	"time"
+)
+
+// PersonFields describes the fields of Person that can be queried.
+var PersonFields = struct {
+	ID        sliceql.Field[Person, int]
+	Created   sliceql.Field[Person, int64]
+	AuditName sliceql.Field[Person, string]
+	Name      sliceql.Field[Person, string]
+	Age       sliceql.Field[Person, int]
+	Score     sliceql.Field[Person, float64]
+	Joined    sliceql.Field[Person, time.Duration]
+}{
+	ID: sliceql.Field[Person, int]{
+		Name: "ID",
+		Get:  func(e Person) int { return e.ID },
+	},
+	Created: sliceql.Field[Person, int64]{
+		Name: "Created",
+		Get: func(e Person) (v int64) {
+			if e.Audit != nil {
+				v = e.Created
+			}
+			return v
+		},
+	},
+	AuditName: sliceql.Field[Person, string]{
+		Name: "Name",
+		Get: func(e Person) (v string) {
+			if e.Audit != nil {
+				v = e.Audit.Name
+			}
+			return v
+		},
+	},
+	Name: sliceql.Field[Person, string]{
+		Name: "name",
+		Get:  func(e Person) string { return e.Name },
+	},
+	Age: sliceql.Field[Person, int]{
+		Name: "age",
+		Get:  func(e Person) int { return e.Age },
+	},
+	Score: sliceql.Field[Person, float64]{
+		Name: "Score",
+		Get:  func(e Person) float64 { return e.Score },
+	},
+	Joined: sliceql.Field[Person, time.Duration]{
+		Name: "Joined",
+		Get:  func(e Person) time.Duration { return e.Joined },
+	},
+}
+
+// EqualPerson reports whether the Person values a and b are equal.
+// It can be passed to Query.Equal.
+//
+// The fields Notify, Extra, Values, Pair are not compared.
+func EqualPerson(a, b Person) bool {
+	return a.Base == b.Base &&
+		a.Audit == b.Audit &&
+		a.Name == b.Name &&
+		a.Age == b.Age &&
+		a.Score == b.Score &&
+		a.Active == b.Active &&
+		a.Joined == b.Joined &&
+		a.Secret == b.Secret &&
+		a.Address == b.Address &&
+		slices.Equal(a.Tags, b.Tags) &&
+		maps.Equal(a.Attrs, b.Attrs) &&
+		a.nickname == b.nickname
+}
+
+// AddressFields describes the fields of Address that can be queried.
+var AddressFields = struct {
+	Street sliceql.Field[Address, string]
+	City   sliceql.Field[Address, string]
+}{
+	Street: sliceql.Field[Address, string]{
+		Name: "Street",
+		Get:  func(e Address) string { return e.Street },
+	},
+	City: sliceql.Field[Address, string]{
+		Name: "city",
+		Get:  func(e Address) string { return e.City },
+	},
+}
+
+// EqualAddress reports whether the Address values a and b are equal.
+// It can be passed to Query.Equal.
+func EqualAddress(a, b Address) bool {
+	return a.Street == b.Street &&
+		a.City == b.City
+}
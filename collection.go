@@ -0,0 +1,102 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import "fmt"
+
+// A DuplicatePolicy tells ToMap what to do with elements whose key is
+// already used by an earlier element.
+type DuplicatePolicy int
+
+const (
+	// DuplicateError stops at the first duplicate key and returns an
+	// error wrapping ErrDuplicateKey.
+	DuplicateError DuplicatePolicy = iota
+	// DuplicateKeepFirst keeps the value of the first element with a key.
+	DuplicateKeepFirst
+	// DuplicateKeepLast keeps the value of the last element with a key.
+	DuplicateKeepLast
+)
+
+// ToMap returns a map from the keys of the elements of the Query, as
+// computed by key, to their values, as computed by value.
+//
+// The policy tells which value a key maps to if several elements share
+// it. With DuplicateError, ToMap stops at the first element whose key
+// is already used and returns a nil map and an *ElementError with the
+// index of that element wrapping ErrDuplicateKey. Use ToMapFunc to
+// combine the values instead. A nil function yields an empty map.
+func ToMap[E any, K comparable, V any](q *Query[E], key func(E) K, value func(E) V, policy DuplicatePolicy) (map[K]V, error) {
+	m := make(map[K]V, len(*q))
+	if key == nil || value == nil {
+		return m, nil
+	}
+	for i, e := range *q {
+		k := key(e)
+		if _, dup := m[k]; dup {
+			switch policy {
+			case DuplicateError:
+				return nil, &ElementError{Op: "ToMap", Index: i, Err: fmt.Errorf("%w %v", ErrDuplicateKey, k)}
+			case DuplicateKeepFirst:
+				continue
+			}
+		}
+		m[k] = value(e)
+	}
+	return m, nil
+}
+
+// ToMapFunc returns a map from the keys of the elements of the Query,
+// as computed by key, to their values, as computed by value, like
+// ToMap.
+//
+// If several elements share a key, their values are combined with
+// merge, from the first to the last: merge is called with the value
+// combined so far and the value of the next element. A nil function
+// yields an empty map.
+func ToMapFunc[E any, K comparable, V any](q *Query[E], key func(E) K, value func(E) V, merge func(V, V) V) map[K]V {
+	m := make(map[K]V, len(*q))
+	if key == nil || value == nil || merge == nil {
+		return m
+	}
+	for _, e := range *q {
+		k, v := key(e), value(e)
+		if old, dup := m[k]; dup {
+			v = merge(old, v)
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// ToLookup returns a map from the keys of the elements of the Query,
+// as computed by key, to the elements with that key.
+//
+// The elements of every key keep their order in the Query. A nil key
+// yields an empty map.
+func ToLookup[E any, K comparable](q *Query[E], key func(E) K) map[K][]E {
+	if key == nil {
+		return make(map[K][]E)
+	}
+	return lookup(q, key)
+}
+
+// ToSet returns the set of the elements of the Query.
+func ToSet[E comparable](q *Query[E]) map[E]struct{} {
+	return keySet(q, identity[E])
+}
+
+// CountBy returns the number of elements of the Query for every key,
+// as computed by key. A nil key yields an empty map.
+func CountBy[E any, K comparable](q *Query[E], key func(E) K) map[K]int {
+	m := make(map[K]int)
+	if key == nil {
+		return m
+	}
+	for _, e := range *q {
+		m[key(e)]++
+	}
+	return m
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ToMap(t *testing.T) {
+	words := &Query[string]{"apple", "avocado", "banana", "blueberry", "cherry"}
+	initial := func(s string) byte {
+		return s[0]
+	}
+	tests := []struct {
+		name      string
+		q         *Query[string]
+		key       func(string) byte
+		policy    DuplicatePolicy
+		want      map[byte]string
+		wantIndex int
+	}{
+		{
+			name:   "nil key",
+			q:      words,
+			policy: DuplicateKeepLast,
+			want:   map[byte]string{},
+		},
+		{
+			name:   "unique keys",
+			q:      &Query[string]{"apple", "banana"},
+			key:    initial,
+			policy: DuplicateError,
+			want:   map[byte]string{'a': "APPLE", 'b': "BANANA"},
+		},
+		{
+			name:      "duplicate error",
+			q:         words,
+			key:       initial,
+			policy:    DuplicateError,
+			wantIndex: 1,
+		},
+		{
+			name:   "keep first",
+			q:      words,
+			key:    initial,
+			policy: DuplicateKeepFirst,
+			want:   map[byte]string{'a': "APPLE", 'b': "BANANA", 'c': "CHERRY"},
+		},
+		{
+			name:   "keep last",
+			q:      words,
+			key:    initial,
+			policy: DuplicateKeepLast,
+			want:   map[byte]string{'a': "AVOCADO", 'b': "BLUEBERRY", 'c': "CHERRY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMap(tt.q, tt.key, strings.ToUpper, tt.policy)
+			if tt.want == nil {
+				var ee *ElementError
+				if !errors.As(err, &ee) || ee.Index != tt.wantIndex || !errors.Is(err, ErrDuplicateKey) {
+					t.Errorf("ToMap() error = %v, want a duplicate key at element %d", err, tt.wantIndex)
+				}
+				if got != nil {
+					t.Errorf("ToMap() = %v, want nil", got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToMap() = %v, %v, want %v, nil", got, err, tt.want)
+			}
+		})
+	}
+	_, err := ToMap(&Query[int]{1, 2, 1}, identity[int], identity[int], DuplicateError)
+	if want := "sliceql.ToMap: element 2: duplicate key 1"; err == nil || err.Error() != want {
+		t.Errorf("ToMap() error = %v, want %q", err, want)
+	}
+}
+
+func Test_ToMapFunc(t *testing.T) {
+	q := &Query[string]{"apple", "avocado", "banana"}
+	initial := func(s string) byte {
+		return s[0]
+	}
+	join := func(a, b string) string {
+		return a + "+" + b
+	}
+	want := map[byte]string{'a': "apple+avocado", 'b': "banana"}
+	if got := ToMapFunc(q, initial, identity[string], join); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMapFunc() = %v, want %v", got, want)
+	}
+	if got := ToMapFunc(q, initial, identity[string], nil); got == nil || len(got) != 0 {
+		t.Errorf("ToMapFunc(nil) = %#v, want an empty map", got)
+	}
+}
+
+func Test_ToLookup(t *testing.T) {
+	q := &Query[int]{1, 2, 3, 4, 5, 6}
+	want := map[bool][]int{false: {1, 3, 5}, true: {2, 4, 6}}
+	if got := ToLookup(q, isEven); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToLookup() = %v, want %v", got, want)
+	}
+	if got := ToLookup[int, bool](q, nil); got == nil || len(got) != 0 {
+		t.Errorf("ToLookup(nil) = %#v, want an empty map", got)
+	}
+}
+
+func Test_ToSet(t *testing.T) {
+	want := map[int]struct{}{1: {}, 2: {}, 3: {}}
+	if got := ToSet(&Query[int]{1, 2, 2, 3, 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToSet() = %v, want %v", got, want)
+	}
+	if got := ToSet(&Query[int]{}); got == nil || len(got) != 0 {
+		t.Errorf("ToSet() on empty Query = %#v, want an empty map", got)
+	}
+}
+
+func Test_CountBy(t *testing.T) {
+	q := &Query[string]{"a", "b", "a", "c", "a", "b"}
+	want := map[string]int{"a": 3, "b": 2, "c": 1}
+	if got := CountBy(q, identity[string]); !reflect.DeepEqual(got, want) {
+		t.Errorf("CountBy() = %v, want %v", got, want)
+	}
+	if got := CountBy[string, string](q, nil); got == nil || len(got) != 0 {
+		t.Errorf("CountBy(nil) = %#v, want an empty map", got)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import "context"
+
+// WhereCtx keeps only the elements that satisfy f, like Where, for
+// tests that can fail or take long, such as lookups in a cache.
+//
+// WhereCtx stops at the first element for which f returns an error, or
+// once ctx is done, and returns an *ElementError with the index of that
+// element. The Query is only modified if every element has been tested.
+// Like Where, WhereCtx returns a new empty Query if the Query is empty
+// or f is nil.
+func (q *Query[E]) WhereCtx(ctx context.Context, f func(context.Context, E) (bool, error)) (*Query[E], error) {
+	if len(*q) < 1 || f == nil {
+		return &Query[E]{}, nil
+	}
+	result := Query[E](make([]E, 0))
+	for i, e := range *q {
+		if err := ctx.Err(); err != nil {
+			return q, &ElementError{Op: "WhereCtx", Index: i, Err: err}
+		}
+		ok, err := f(ctx, e)
+		if err != nil {
+			return q, &ElementError{Op: "WhereCtx", Index: i, Err: err}
+		}
+		if ok {
+			result = append(result, e)
+		}
+	}
+	*q = result
+	q.touch()
+	return q, nil
+}
+
+// EachCtx replaces every element with the result of f, like Each, for
+// functions that can fail or take long.
+//
+// EachCtx stops at the first element for which f returns an error, or
+// once ctx is done, and returns an *ElementError with the index of that
+// element. The Query is only modified if f has succeeded for every
+// element. A nil f leaves the Query unchanged.
+func (q *Query[E]) EachCtx(ctx context.Context, f func(context.Context, E) (E, error)) (*Query[E], error) {
+	result, err := selectErr(ctx, "EachCtx", q, f)
+	if err != nil {
+		return q, err
+	}
+	copy(*q, *result)
+	q.touch()
+	return q, nil
+}
+
+// SelectErr projects each element of the Query into a new form, like
+// Select, for functions that can fail or take long.
+//
+// SelectErr stops at the first element for which f returns an error,
+// or once ctx is done, and returns an *ElementError with the index of
+// that element. The source Query is not modified. A nil f yields an
+// empty Query.
+func SelectErr[E, R any](ctx context.Context, q *Query[E], f func(context.Context, E) (R, error)) (*Query[R], error) {
+	return selectErr(ctx, "SelectErr", q, f)
+}
+
+// selectErr implements SelectErr and EachCtx, naming op in its errors.
+func selectErr[E, R any](ctx context.Context, op string, q *Query[E], f func(context.Context, E) (R, error)) (*Query[R], error) {
+	if f == nil {
+		return &Query[R]{}, nil
+	}
+	result := Query[R](make([]R, len(*q)))
+	for i, e := range *q {
+		if err := ctx.Err(); err != nil {
+			return nil, &ElementError{Op: op, Index: i, Err: err}
+		}
+		r, err := f(ctx, e)
+		if err != nil {
+			return nil, &ElementError{Op: op, Index: i, Err: err}
+		}
+		result[i] = r
+	}
+	return &result, nil
+}
+
+// FoldErr applies f to each element in the Query like Aggregate, for
+// functions that can fail or take long.
+//
+// FoldErr stops at the first element for which f returns an error, or
+// once ctx is done, and returns the zero value of A and an
+// *ElementError with the index of that element. A nil f returns seed.
+func FoldErr[E, A any](ctx context.Context, q *Query[E], seed A, f func(context.Context, A, E) (A, error)) (A, error) {
+	result := seed
+	if f == nil {
+		return result, nil
+	}
+	for i, e := range *q {
+		var zero A
+		if err := ctx.Err(); err != nil {
+			return zero, &ElementError{Op: "FoldErr", Index: i, Err: err}
+		}
+		acc, err := f(ctx, result, e)
+		if err != nil {
+			return zero, &ElementError{Op: "FoldErr", Index: i, Err: err}
+		}
+		result = acc
+	}
+	return result, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+var errOdd = errors.New("odd element")
+
+// failAt returns an error for the element fail.
+func failAt(fail int) func(context.Context, int) (int, error) {
+	return func(_ context.Context, e int) (int, error) {
+		if e == fail {
+			return 0, errOdd
+		}
+		return e * 10, nil
+	}
+}
+
+func TestQuery_WhereCtx(t *testing.T) {
+	even := func(_ context.Context, e int) (bool, error) {
+		if e < 0 {
+			return false, errOdd
+		}
+		return e%2 == 0, nil
+	}
+	tests := []struct {
+		name      string
+		q         *Query[int]
+		f         func(context.Context, int) (bool, error)
+		want      []int
+		wantIndex int
+		wantErr   error
+	}{
+		{
+			name: "nil test",
+			q:    &Query[int]{1, 2, 3},
+			want: []int{},
+		},
+		{
+			name: "empty slice",
+			q:    &Query[int]{},
+			f:    even,
+			want: []int{},
+		},
+		{
+			name: "non-empty slice",
+			q:    &Query[int]{1, 2, 3, 4},
+			f:    even,
+			want: []int{2, 4},
+		},
+		{
+			name:      "failing test",
+			q:         &Query[int]{1, 2, -3, 4},
+			f:         even,
+			want:      []int{1, 2, -3, 4},
+			wantIndex: 2,
+			wantErr:   errOdd,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.q.WhereCtx(context.Background(), tt.f)
+			checkElementError(t, err, "WhereCtx", tt.wantIndex, tt.wantErr)
+			if !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Query.WhereCtx() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_EachCtx(t *testing.T) {
+	tests := []struct {
+		name      string
+		q         *Query[int]
+		f         func(context.Context, int) (int, error)
+		want      []int
+		wantIndex int
+		wantErr   error
+	}{
+		{
+			name: "nil function",
+			q:    &Query[int]{1, 2, 3},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "non-empty slice",
+			q:    &Query[int]{1, 2, 3},
+			f:    failAt(-1),
+			want: []int{10, 20, 30},
+		},
+		{
+			name:      "failing function",
+			q:         &Query[int]{1, 2, 3},
+			f:         failAt(3),
+			want:      []int{1, 2, 3},
+			wantIndex: 2,
+			wantErr:   errOdd,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.q.EachCtx(context.Background(), tt.f)
+			checkElementError(t, err, "EachCtx", tt.wantIndex, tt.wantErr)
+			if got != tt.q || !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Query.EachCtx() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SelectErr(t *testing.T) {
+	itoa := func(_ context.Context, e int) (string, error) {
+		if e < 0 {
+			return "", errOdd
+		}
+		return strconv.Itoa(e), nil
+	}
+	q := &Query[int]{1, 2, 3}
+	got, err := SelectErr(context.Background(), q, itoa)
+	if err != nil || !reflect.DeepEqual(got.ToSlice(), []string{"1", "2", "3"}) {
+		t.Errorf("SelectErr() = %v, %v, want [1 2 3], nil", got, err)
+	}
+	got, err = SelectErr[int, string](context.Background(), q, nil)
+	if err != nil || len(*got) != 0 {
+		t.Errorf("SelectErr(nil) = %v, %v, want [], nil", got, err)
+	}
+	got, err = SelectErr(context.Background(), &Query[int]{1, -2, 3}, itoa)
+	checkElementError(t, err, "SelectErr", 1, errOdd)
+	if got != nil {
+		t.Errorf("SelectErr() = %v, want nil", got)
+	}
+}
+
+func Test_FoldErr(t *testing.T) {
+	sum := func(_ context.Context, acc, e int) (int, error) {
+		if e < 0 {
+			return acc, errOdd
+		}
+		return acc + e, nil
+	}
+	q := &Query[int]{1, 2, 3}
+	if got, err := FoldErr(context.Background(), q, 10, sum); got != 16 || err != nil {
+		t.Errorf("FoldErr() = %v, %v, want 16, nil", got, err)
+	}
+	if got, err := FoldErr[int, int](context.Background(), q, 10, nil); got != 10 || err != nil {
+		t.Errorf("FoldErr(nil) = %v, %v, want 10, nil", got, err)
+	}
+	got, err := FoldErr(context.Background(), &Query[int]{1, 2, -3}, 10, sum)
+	checkElementError(t, err, "FoldErr", 2, errOdd)
+	if got != 0 {
+		t.Errorf("FoldErr() = %v, want 0", got)
+	}
+}
+
+func TestQuery_Ctx_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	f := func(_ context.Context, e int) (int, error) {
+		if calls++; calls == 2 {
+			cancel()
+		}
+		return e, nil
+	}
+	q := &Query[int]{1, 2, 3, 4}
+	_, err := q.EachCtx(ctx, f)
+	checkElementError(t, err, "EachCtx", 2, context.Canceled)
+	if calls != 2 {
+		t.Errorf("EachCtx() called f %d times, want 2", calls)
+	}
+
+	_, err = q.WhereCtx(ctx, func(context.Context, int) (bool, error) {
+		t.Fatal("WhereCtx() called f after cancellation")
+		return false, nil
+	})
+	checkElementError(t, err, "WhereCtx", 0, context.Canceled)
+}
+
+func TestQuery_WhereCtx_Index(t *testing.T) {
+	q := &Query[int]{1, 2, 3, 4}
+	ix := NewHashIndex(q, isEven)
+	defer ix.Close()
+	if _, err := q.WhereCtx(context.Background(), func(_ context.Context, e int) (bool, error) {
+		return e > 2, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ix.Count(true); got != 1 {
+		t.Errorf("HashIndex.Count() after WhereCtx() = %d, want 1", got)
+	}
+}
+
+// checkElementError checks that err is an *ElementError of op at index
+// wrapping target, or nil if target is nil.
+func checkElementError(t *testing.T, err error, op string, index int, target error) {
+	t.Helper()
+	if target == nil {
+		if err != nil {
+			t.Errorf("error = %v, want nil", err)
+		}
+		return
+	}
+	var ee *ElementError
+	if !errors.As(err, &ee) {
+		t.Errorf("error = %v, want an *ElementError", err)
+		return
+	}
+	if ee.Op != op || ee.Index != index || !errors.Is(err, target) {
+		t.Errorf("error = %v, want %s at element %d wrapping %v", err, op, index, target)
+	}
+	if want := "sliceql." + op + ": element " + strconv.Itoa(index) + ": " + target.Error(); err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
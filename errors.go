@@ -0,0 +1,57 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned, or raised as panics, by element access operations.
+// They are wrapped with the name of the failing operation, so test for
+// them with errors.Is.
+var (
+	// ErrEmpty reports an access to an element of an empty query.
+	ErrEmpty = errors.New("empty list")
+
+	// ErrOutOfRange reports an access to an index outside of a query.
+	ErrOutOfRange = errors.New("index out of bounds")
+)
+
+// ErrDuplicateKey reports an element whose key is already used by an
+// earlier element, for operations that require unique keys, such as
+// ToMap with DuplicateError.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// ErrInvalidFrame reports a Frame that starts after it ends, raised as
+// a panic by the window aggregates such as WindowSum.
+var ErrInvalidFrame = errors.New("frame starts after it ends")
+
+// An ElementError reports the element at which an operation stopped,
+// such as WhereCtx or SelectErr when their function fails, or ToMap at
+// a duplicate key.
+//
+// Err is the error returned by the function for the element, the error
+// of the context if it was done before the element was processed, or
+// an error wrapping ErrDuplicateKey.
+type ElementError struct {
+	// Op is the name of the operation, such as WhereCtx.
+	Op string
+	// Index is the index of the element in the Query.
+	Index int
+	// Err is the error the operation stopped with.
+	Err error
+}
+
+// Error returns the error as "sliceql.Op: element Index: Err".
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("sliceql.%s: element %d: %v", e.Op, e.Index, e.Err)
+}
+
+// Unwrap returns Err, so that errors.Is and errors.As see through the
+// ElementError.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuery_ElementAt(t *testing.T) {
+	type args struct {
+		q *Query[int]
+		i int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr error
+	}{
+		{
+			name: "valid index",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				i: 2,
+			},
+			want: 3,
+		},
+		{
+			name: "negative index",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				i: -1,
+			},
+			wantErr: ErrOutOfRange,
+		},
+		{
+			name: "index out of range",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				i: 10,
+			},
+			wantErr: ErrOutOfRange,
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q: &Query[int]{},
+				i: 0,
+			},
+			wantErr: ErrEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.args.q.ElementAt(tt.args.i)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Query.ElementAt() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Query.ElementAt() = %v, want %v", got, tt.want)
+			}
+			if got := tt.args.q.AtOr(tt.args.i, -1); tt.wantErr != nil && got != -1 {
+				t.Errorf("Query.AtOr() = %v, want %v", got, -1)
+			}
+		})
+	}
+}
+
+func TestQuery_TryFirstLast(t *testing.T) {
+	q := &Query[int]{1, 2, 3}
+	if got, err := q.TryFirst(); got != 1 || err != nil {
+		t.Errorf("Query.TryFirst() = %v, %v, want %v, nil", got, err, 1)
+	}
+	if got, err := q.TryLast(); got != 3 || err != nil {
+		t.Errorf("Query.TryLast() = %v, %v, want %v, nil", got, err, 3)
+	}
+	empty := &Query[int]{}
+	if _, err := empty.TryFirst(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Query.TryFirst() error = %v, want %v", err, ErrEmpty)
+	}
+	if _, err := empty.TryLast(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Query.TryLast() error = %v, want %v", err, ErrEmpty)
+	}
+	if got := empty.FirstOrDefault(7); got != 7 {
+		t.Errorf("Query.FirstOrDefault() = %v, want %v", got, 7)
+	}
+	if got := empty.LastOrDefault(7); got != 7 {
+		t.Errorf("Query.LastOrDefault() = %v, want %v", got, 7)
+	}
+	if got := q.LastOrDefault(7); got != 3 {
+		t.Errorf("Query.LastOrDefault() = %v, want %v", got, 3)
+	}
+}
+
+func TestQuery_PanicErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+		want error
+		msg  string
+	}{
+		{
+			name: "at",
+			f:    func() { (&Query[int]{1}).At(1) },
+			want: ErrOutOfRange,
+			msg:  "sliceql.At: index out of bounds",
+		},
+		{
+			name: "first",
+			f:    func() { (&Query[int]{}).First() },
+			want: ErrEmpty,
+			msg:  "sliceql.First: empty list",
+		},
+		{
+			name: "last",
+			f:    func() { (&Query[int]{}).Last() },
+			want: ErrEmpty,
+			msg:  "sliceql.Last: empty list",
+		},
+		{
+			name: "lazy first",
+			f:    func() { NewLazy([]int{}).First() },
+			want: ErrEmpty,
+			msg:  "sliceql.First: empty list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("recovered %v, want %v", err, tt.want)
+				}
+				if err.Error() != tt.msg {
+					t.Errorf("recovered %q, want %q", err.Error(), tt.msg)
+				}
+			}()
+			tt.f()
+		})
+	}
+}
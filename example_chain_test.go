@@ -47,3 +47,161 @@ func Example() {
 	// [Bob: 31 Jenny: 26 John: 42 Michael: 17]
 	// John: 42
 }
+
+// ExampleSelect projects a query of Person
+// values into a query of their names.
+func ExampleSelect() {
+	s := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+	})
+
+	names := Select(s.Where(func(p Person) bool {
+		// Filter by age > 30.
+		return p.Age > 30
+	}), func(p Person) string {
+		return p.Name
+	})
+	fmt.Println(names)
+
+	// Output:
+	// [Bob John]
+}
+
+// ExampleGroupBy groups people by age bracket
+// and counts the members of each group.
+func ExampleGroupBy() {
+	s := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+		{"Michael", 17},
+	})
+
+	groups := GroupBy(s, func(p Person) bool {
+		return p.Age > 30
+	})
+	for _, g := range *groups {
+		fmt.Println(g.Key, len(*g.Elements))
+	}
+
+	// Output:
+	// true 2
+	// false 2
+}
+
+// ExampleRun filters and sorts a query with
+// the SliceQL query language.
+func ExampleRun() {
+	s := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+		{"Michael", 17},
+	})
+
+	q, err := Run(s, "WHERE Age > 20 AND Name LIKE 'J%' ORDER BY Age DESC LIMIT 1")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(q)
+
+	_, err = Run(s, "WHERE Agee > 20")
+	fmt.Println(err)
+
+	// Output:
+	// [John: 42]
+	// sliceql: column 7: unknown field "Agee"
+}
+
+func ExampleReport() {
+	s := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+		{"Michael", 17},
+	})
+
+	rows, err := Report(s, "SELECT Age >= 18 AS adult, COUNT(*) AS n, MAX(Age) AS oldest GROUP BY Age >= 18 ORDER BY n DESC")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, r := range rows {
+		fmt.Println(r["adult"], r["n"], r["oldest"])
+	}
+
+	// Output:
+	// true 3 42
+	// false 1 17
+}
+
+func ExampleField() {
+	age := Field[Person, int]{Name: "Age", Get: func(p Person) int { return p.Age }}
+	name := Field[Person, string]{Name: "Name", Get: func(p Person) string { return p.Name }}
+
+	p := And(age.Gt(20), Not(name.In("Bob", "Jane")))
+	q := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+		{"Michael", 17},
+	}).Where(p.Test)
+
+	fmt.Println(p)
+	fmt.Println(q)
+
+	// Output:
+	// Age > 20 AND NOT Name IN ('Bob', 'Jane')
+	// [Jenny: 26 John: 42]
+}
+
+// ExampleOver ranks people by age and computes
+// the age difference to the next older person.
+func ExampleOver() {
+	age := Field[Person, int]{Name: "Age", Get: func(p Person) int { return p.Age }}
+	s := NewQuery([]Person{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 42},
+		{"Michael", 17},
+	})
+
+	w := Over(s).OrderByDescendingFunc(age.Compare)
+	ranks := w.Rank()
+	older := Lag(w, age.Get, 1, 0)
+	for i, r := range *ranks {
+		if o := (*older)[i].Second; o > 0 {
+			fmt.Println(r.Second, r.First, o-r.First.Age)
+		} else {
+			fmt.Println(r.Second, r.First)
+		}
+	}
+
+	// Output:
+	// 2 Bob: 31 11
+	// 3 Jenny: 26 5
+	// 1 John: 42
+	// 4 Michael: 17 9
+}
+
+// ExampleWindowSum compares the daily sales with
+// the sum of the two days before.
+func ExampleWindowSum() {
+	sales := NewQuery([]int{5, 3, 4, 9, 2})
+	before := WindowSum(Over(sales), Frame{Start: -2, End: -1}, func(n int) int {
+		return n
+	})
+	for _, p := range *before {
+		fmt.Println(p.First, p.Second)
+	}
+
+	// Output:
+	// 5 0
+	// 3 5
+	// 4 8
+	// 9 7
+	// 2 13
+}
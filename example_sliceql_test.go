@@ -67,3 +67,99 @@ func ExampleQuery_At() {
 	// Output:
 	// 3
 }
+
+// ExampleQuery_Lazy defers a filter until the first
+// matching element is requested.
+func ExampleQuery_Lazy() {
+	// Lazy() records stages and only pulls elements
+	// when a terminal operation like First() runs.
+	s := NewQuery[int]([]int{1, 2, 3, 4, 5})
+	fmt.Println(s.Lazy().Where(func(e int) bool {
+		return e > 2
+	}).First())
+
+	// Output:
+	// 3
+}
+
+// ExampleQuery_Seq2 ranges over the indexes and
+// elements of a slice.
+func ExampleQuery_Seq2() {
+	// Seq2() returns an iterator for range loops that
+	// stops as soon as the loop breaks.
+	s := NewQuery[string]([]string{"a", "b", "c", "d"})
+	for i, e := range s.Seq2() {
+		if i == 2 {
+			break
+		}
+		fmt.Println(i, e)
+	}
+
+	// Output:
+	// 0 a
+	// 1 b
+}
+
+// ExampleWindow computes a moving average
+// over sliding windows.
+func ExampleWindow() {
+	// Window() returns every run of size elements,
+	// starting every step elements.
+	s := NewQuery[int]([]int{2, 4, 6, 8, 10})
+	avg := Select(Window(s, 3, 1), func(w []int) int {
+		return (w[0] + w[1] + w[2]) / 3
+	})
+	fmt.Println(avg.String())
+
+	// Output:
+	// [4 6 8]
+}
+
+// ExampleMergeSorted merges sorted shards
+// into a single sorted slice.
+func ExampleMergeSorted() {
+	// MergeSorted() takes the smallest head of the
+	// shards in turn, without sorting them again.
+	a := NewQuery[int]([]int{1, 4, 9})
+	b := NewQuery[int]([]int{2, 3, 10})
+	c := NewQuery[int]([]int{5})
+	fmt.Println(MergeSorted(func(e1, e2 int) int {
+		return e1 - e2
+	}, a, b, c).String())
+
+	// Output:
+	// [1 2 3 4 5 9 10]
+}
+
+// ExampleQuery_Parallel filters a slice on several workers,
+// keeping the order of the elements.
+func ExampleQuery_Parallel() {
+	// Parallel() splits the elements into chunks that
+	// a pool of workers filters concurrently.
+	s := NewQuery[int]([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	fmt.Println(s.Parallel(4).Where(func(e int) bool {
+		return e%2 == 0
+	}).Query().String())
+
+	// Output:
+	// [2 4 6 8]
+}
+
+func ExampleLazy_Explain() {
+	// Explain() shows the stages of a pipeline, the last
+	// one first, after Sort() and Take() have been rewritten
+	// into a top-k selection. Profile() adds statistics of
+	// the last run.
+	s := NewQuery[int]([]int{5, 3, 8, 1})
+	fmt.Print(s.Lazy().Where(func(e int) bool {
+		return e > 2
+	}).Sort(func(e1, e2 int) bool {
+		return e1 < e2
+	}).Take(2).Explain())
+
+	// Output:
+	// Take(2)
+	// └─ TopK(2)
+	//    └─ Where
+	//       └─ Slice(4)
+}
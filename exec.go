@@ -0,0 +1,870 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Program is a query compiled for elements of type E.
+//
+// Compiling resolves every field name of the query against E and checks
+// that the operands of every operator have compatible types, so running
+// a Program cannot fail. A Program is safe for concurrent use.
+type Program[E any] struct {
+	stmt    *Statement
+	typ     reflect.Type
+	where   evaluator
+	grouped bool
+	groupBy []evaluator
+	having  evaluator
+	orderBy []compiledOrder
+	columns []compiledColumn
+}
+
+// evaluator computes the value of a compiled expression.
+type evaluator func(*env) any
+
+// env is the environment an evaluator runs in.
+type env struct {
+	// row is the struct value of the current element. In a grouped
+	// query it is the first element of the group, which provides the
+	// values of the GROUP BY expressions.
+	row reflect.Value
+	// group holds the struct values of the elements of the current
+	// group in a grouped query.
+	group []reflect.Value
+}
+
+// compiledOrder is a compiled key of an ORDER BY clause.
+type compiledOrder struct {
+	eval evaluator
+	desc bool
+}
+
+// compiledColumn is a compiled column of a SELECT clause.
+type compiledColumn struct {
+	name  string
+	pos   int
+	class class
+	// eval computes the normalized value of the column,
+	// and value the value reported in a row.
+	eval  evaluator
+	value evaluator
+	// omitEmpty reports whether the column is left out of a row
+	// if its value is empty.
+	omitEmpty bool
+}
+
+// Compile parses the text of a query and resolves it against the
+// element type E, which must be a struct or a pointer to a struct.
+//
+// Errors in the text or unknown field names are returned as *ParseError
+// values that point at the offending column of src.
+func Compile[E any](src string) (*Program[E], error) {
+	stmt, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sliceql: cannot query elements of non-struct type %v", typ)
+	}
+	c := &compiler{schema: schemaOf(typ), clause: "WHERE"}
+	p := &Program[E]{stmt: stmt, typ: typ}
+	if stmt.where != nil {
+		x, err := c.condition(stmt.where, c.clause)
+		if err != nil {
+			return nil, err
+		}
+		p.where = x.eval
+	}
+	c.clause = "GROUP BY"
+	c.groupKeys = make(map[string]bool)
+	for _, k := range stmt.groupBy {
+		x, err := c.compile(k)
+		if err != nil {
+			return nil, err
+		}
+		p.groupBy = append(p.groupBy, x.eval)
+		c.groupKeys[k.String()] = true
+	}
+	p.grouped = len(stmt.groupBy) > 0 || stmt.having != nil ||
+		slices.ContainsFunc(stmt.columns, func(col column) bool {
+			return hasAggregate(col.x)
+		}) ||
+		slices.ContainsFunc(stmt.orderBy, func(t orderTerm) bool {
+			return hasAggregate(t.x)
+		})
+	c.grouped, c.aggregates = p.grouped, true
+	c.clause = "SELECT"
+	if p.columns, err = c.columns(stmt.columns, stmt.groupBy); err != nil {
+		return nil, err
+	}
+	if stmt.having != nil {
+		c.clause = "HAVING"
+		x, err := c.condition(stmt.having, c.clause)
+		if err != nil {
+			return nil, err
+		}
+		p.having = x.eval
+	}
+	c.clause = "ORDER BY"
+	for _, t := range stmt.orderBy {
+		x, err := c.orderKey(t.x, stmt.columns, p.columns)
+		if err != nil {
+			return nil, err
+		}
+		p.orderBy = append(p.orderBy, compiledOrder{eval: x.eval, desc: t.desc})
+	}
+	return p, nil
+}
+
+// Run compiles the query src for the elements of q and runs it.
+//
+// It is a shorthand for Compile followed by Program.Run.
+func Run[E any](q *Query[E], src string) (*Query[E], error) {
+	p, err := Compile[E](src)
+	if err != nil {
+		return nil, err
+	}
+	return p.Run(q), nil
+}
+
+// String returns the query of the Program in a normalized form.
+func (p *Program[E]) String() string {
+	return p.stmt.String()
+}
+
+// Run applies the WHERE, ORDER BY, OFFSET and LIMIT clauses of the
+// Program to the elements of q, in that order.
+//
+// The SELECT clause is ignored, since the result keeps the element type;
+// use Rows for projected columns. In a grouped query, the clauses after
+// WHERE apply to groups, and the result holds the elements of the
+// remaining groups, one group after the other. The ordering is stable.
+// The function returns a pointer to a new Query. The source Query is not
+// modified.
+func (p *Program[E]) Run(q *Query[E]) *Query[E] {
+	result := Query[E](make([]E, 0))
+	for _, r := range p.execute(q) {
+		for _, i := range r.members {
+			result = append(result, (*q)[i])
+		}
+	}
+	return &result
+}
+
+// Rows runs the Program on the elements of q like Run and projects
+// every resulting element, or every resulting group in a grouped query,
+// to a row of the columns of the SELECT clause.
+//
+// Without a SELECT clause, or with SELECT *, a row has one entry for
+// every exported field of the element, named after its sliceql or json
+// tag, and fields tagged omitempty are left out if they are empty, as
+// in encoding/json. A grouped query without a SELECT
+// clause has a column for every GROUP BY expression and COUNT(*).
+// Columns that name a field hold the field value as is, other columns
+// hold a bool, an int64, a float64 or a string, and NULL values are
+// stored as nil.
+func (p *Program[E]) Rows(q *Query[E]) []map[string]any {
+	results := p.execute(q)
+	rows := make([]map[string]any, len(results))
+	for i, r := range results {
+		row := make(map[string]any, len(p.columns))
+		for _, c := range p.columns {
+			v := c.value(r.env)
+			if c.omitEmpty && isEmpty(v) {
+				continue
+			}
+			row[c.name] = v
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// result is a row produced by a Program before projection.
+type result struct {
+	env *env
+	// members are the indexes of the elements of the row.
+	members []int
+	keys    []any
+}
+
+// execute runs every clause of the Program but SELECT on q.
+func (p *Program[E]) execute(q *Query[E]) []result {
+	results := make([]result, 0)
+	for i, e := range *q {
+		en := &env{row: p.value(e)}
+		if p.where != nil && !truth(p.where(en)) {
+			continue
+		}
+		results = append(results, result{env: en, members: []int{i}})
+	}
+	if p.grouped {
+		results = p.group(results)
+	}
+	if len(p.orderBy) > 0 {
+		for i := range results {
+			r := &results[i]
+			for _, o := range p.orderBy {
+				r.keys = append(r.keys, o.eval(r.env))
+			}
+		}
+		slices.SortStableFunc(results, func(a, b result) int {
+			for i, o := range p.orderBy {
+				c := orderValues(a.keys[i], b.keys[i])
+				if o.desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+	results = results[min(p.stmt.offset, len(results)):]
+	if p.stmt.limit >= 0 {
+		results = results[:min(p.stmt.limit, len(results))]
+	}
+	return results
+}
+
+// group merges the rows of single elements into one row per group,
+// in the order in which the groups were first seen, and applies the
+// HAVING clause. Without GROUP BY all rows form a single group.
+func (p *Program[E]) group(rows []result) []result {
+	var groups []result
+	index := make(map[string]int)
+	if len(p.groupBy) == 0 {
+		groups = append(groups, result{env: &env{}})
+		index[""] = 0
+	}
+	for _, r := range rows {
+		var key strings.Builder
+		for _, k := range p.groupBy {
+			writeKey(&key, k(r.env))
+		}
+		i, ok := index[key.String()]
+		if !ok {
+			i = len(groups)
+			index[key.String()] = i
+			groups = append(groups, result{env: &env{row: r.env.row}})
+		}
+		g := &groups[i]
+		g.env.group = append(g.env.group, r.env.row)
+		g.members = append(g.members, r.members...)
+	}
+	if p.having != nil {
+		groups = slices.DeleteFunc(groups, func(g result) bool {
+			return !truth(p.having(g.env))
+		})
+	}
+	return groups
+}
+
+// writeKey appends an unambiguous encoding of the normalized value v
+// to the grouping key b.
+func writeKey(b *strings.Builder, v any) {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		s = "n"
+	case bool:
+		s = "b" + strconv.FormatBool(v)
+	case int64:
+		s = "i" + strconv.FormatInt(v, 10)
+	case float64:
+		s = "f" + strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		s = "s" + v
+	default:
+		s = fmt.Sprintf("v%#v", v)
+	}
+	b.WriteString(strconv.Itoa(len(s)))
+	b.WriteByte(':')
+	b.WriteString(s)
+}
+
+// value returns the struct value of the element e.
+func (p *Program[E]) value(e E) reflect.Value {
+	v := reflect.ValueOf(&e).Elem()
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	return v
+}
+
+// class is the static type of a compiled expression.
+type class int
+
+const (
+	classAny class = iota
+	classNull
+	classBool
+	classNumber
+	classString
+	classOther
+)
+
+// String returns the name of the class for error messages.
+func (c class) String() string {
+	return [...]string{"any", "NULL", "bool", "number", "string", "value"}[c]
+}
+
+// ordered reports whether values of the class can be ordered.
+func (c class) ordered() bool {
+	return c == classAny || c == classBool || c == classNumber || c == classString
+}
+
+// compatible reports whether values of the classes can be compared.
+func (c class) compatible(d class) bool {
+	return c == d || c == classAny || d == classAny || c == classNull || d == classNull
+}
+
+// classOf returns the class of values of the type t.
+func classOf(t reflect.Type) class {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return classBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return classNumber
+	case reflect.String:
+		return classString
+	case reflect.Interface:
+		return classAny
+	}
+	return classOther
+}
+
+// compiled is a compiled expression and its static class.
+type compiled struct {
+	eval  evaluator
+	class class
+}
+
+// compiler compiles expressions for elements of a struct type.
+type compiler struct {
+	schema *schema
+	// clause names the clause being compiled, for error messages.
+	clause string
+	// aggregates reports whether aggregate functions are allowed.
+	aggregates bool
+	// grouped reports whether the query is grouped. Fields may then
+	// only be used in aggregates or in the GROUP BY expressions listed
+	// in groupKeys.
+	grouped   bool
+	groupKeys map[string]bool
+	// inAggregate reports whether the argument of an aggregate
+	// function is being compiled.
+	inAggregate bool
+}
+
+// condition compiles x, which must be a boolean condition of clause.
+func (c *compiler) condition(x expr, clause string) (compiled, error) {
+	r, err := c.compile(x)
+	if err != nil {
+		return r, err
+	}
+	if r.class != classBool && r.class != classAny {
+		return r, errorAt(x.pos(), "%s requires a condition, found %s of type %v", clause, x, r.class)
+	}
+	return r, nil
+}
+
+// columns compiles the columns of a SELECT clause.
+//
+// Without columns every exported field of the element type is selected,
+// or in a grouped query every GROUP BY expression and COUNT(*).
+func (c *compiler) columns(cols []column, groupBy []expr) ([]compiledColumn, error) {
+	if len(cols) == 0 && c.grouped {
+		for _, k := range groupBy {
+			cols = append(cols, column{x: k})
+		}
+		cols = append(cols, column{x: &callExpr{fn: "COUNT"}})
+	}
+	if len(cols) == 0 {
+		var result []compiledColumn
+		for _, f := range c.schema.fields {
+			get := f.get
+			result = append(result, compiledColumn{
+				name:      f.name,
+				class:     classOf(f.typ),
+				omitEmpty: f.omitEmpty,
+				eval: func(en *env) any {
+					return normalize(get(en.row))
+				},
+				value: func(en *env) any {
+					return raw(get(en.row))
+				},
+			})
+		}
+		return result, nil
+	}
+	result := make([]compiledColumn, len(cols))
+	seen := make(map[string]bool)
+	for i, col := range cols {
+		name := col.name()
+		if seen[name] {
+			return nil, errorAt(col.x.pos(), "duplicate column %q", name)
+		}
+		seen[name] = true
+		x, err := c.compile(col.x)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = compiledColumn{name: name, pos: col.x.pos(), class: x.class, eval: x.eval, value: x.eval}
+		if id, ok := col.x.(*identExpr); ok {
+			// Report fields with their own type rather than normalized.
+			get, _, _ := c.schema.resolve(id.name)
+			result[i].value = func(en *env) any {
+				return raw(get(en.row))
+			}
+		}
+	}
+	return result, nil
+}
+
+// orderKey compiles the ORDER BY key x. A key that names a column of
+// the SELECT clause orders by the value of that column.
+func (c *compiler) orderKey(x expr, cols []column, compiledCols []compiledColumn) (compiled, error) {
+	if id, ok := x.(*identExpr); ok {
+		for i, col := range cols {
+			if col.alias == id.name {
+				r := compiledCols[i]
+				if !r.class.ordered() {
+					return compiled{}, errorAt(x.pos(), "cannot order by %s of type %v", x, r.class)
+				}
+				return compiled{eval: r.eval, class: r.class}, nil
+			}
+		}
+	}
+	r, err := c.compile(x)
+	if err != nil {
+		return r, err
+	}
+	if !r.class.ordered() {
+		return r, errorAt(x.pos(), "cannot order by %s of type %v", x, r.class)
+	}
+	return r, nil
+}
+
+// compile compiles the expression x.
+func (c *compiler) compile(x expr) (compiled, error) {
+	if c.grouped && !c.inAggregate && c.groupKeys[x.String()] {
+		// A GROUP BY expression has the same value for every element
+		// of a group, so it is evaluated on the first one.
+		c.grouped = false
+		defer func() {
+			c.grouped = true
+		}()
+	}
+	switch x := x.(type) {
+	case *identExpr:
+		if c.grouped && !c.inAggregate {
+			return compiled{}, errorAt(x.p, "%s must appear in GROUP BY or be used in an aggregate function", x)
+		}
+		get, typ, err := c.schema.resolve(x.name)
+		if err != nil {
+			return compiled{}, errorAt(x.p, "%v", err)
+		}
+		return compiled{class: classOf(typ), eval: func(en *env) any {
+			return normalize(get(en.row))
+		}}, nil
+	case *literalExpr:
+		v := x.value
+		cl := classNull
+		switch v.(type) {
+		case bool:
+			cl = classBool
+		case int64, float64:
+			cl = classNumber
+		case string:
+			cl = classString
+		}
+		return compiled{class: cl, eval: func(*env) any {
+			return v
+		}}, nil
+	case *notExpr:
+		y, err := c.condition(x.x, "NOT")
+		if err != nil {
+			return y, err
+		}
+		return compiled{class: classBool, eval: func(en *env) any {
+			return !truth(y.eval(en))
+		}}, nil
+	case *logicalExpr:
+		l, err := c.condition(x.x, x.op)
+		if err != nil {
+			return l, err
+		}
+		r, err := c.condition(x.y, x.op)
+		if err != nil {
+			return r, err
+		}
+		if x.op == "AND" {
+			return compiled{class: classBool, eval: func(en *env) any {
+				return truth(l.eval(en)) && truth(r.eval(en))
+			}}, nil
+		}
+		return compiled{class: classBool, eval: func(en *env) any {
+			return truth(l.eval(en)) || truth(r.eval(en))
+		}}, nil
+	case *compareExpr:
+		l, r, err := c.operands(x, x.op != "=" && x.op != "!=", x.x, x.y)
+		if err != nil {
+			return compiled{}, err
+		}
+		test := compareOps[x.op]
+		return compiled{class: classBool, eval: func(en *env) any {
+			return test(l.eval(en), r.eval(en))
+		}}, nil
+	case *inExpr:
+		v, err := c.compile(x.x)
+		if err != nil {
+			return v, err
+		}
+		list := make([]evaluator, len(x.list))
+		for i, e := range x.list {
+			_, r, err := c.operands(x, false, x.x, e)
+			if err != nil {
+				return r, err
+			}
+			list[i] = r.eval
+		}
+		return compiled{class: classBool, eval: func(en *env) any {
+			a := v.eval(en)
+			for _, e := range list {
+				if equalValues(a, e(en)) {
+					return !x.not
+				}
+			}
+			return x.not
+		}}, nil
+	case *likeExpr:
+		v, err := c.compile(x.x)
+		if err != nil {
+			return v, err
+		}
+		if v.class != classString && v.class != classAny {
+			return v, errorAt(x.x.pos(), "LIKE requires a string, found %s of type %v", x.x, v.class)
+		}
+		return compiled{class: classBool, eval: func(en *env) any {
+			s, ok := v.eval(en).(string)
+			return ok && x.re.MatchString(s) != x.not
+		}}, nil
+	case *betweenExpr:
+		v, lo, err := c.operands(x, true, x.x, x.lo)
+		if err != nil {
+			return v, err
+		}
+		_, hi, err := c.operands(x, true, x.x, x.hi)
+		if err != nil {
+			return v, err
+		}
+		return compiled{class: classBool, eval: func(en *env) any {
+			a := v.eval(en)
+			return (lessOrEqual(lo.eval(en), a) && lessOrEqual(a, hi.eval(en))) != x.not
+		}}, nil
+	case *callExpr:
+		return c.aggregate(x)
+	}
+	return compiled{}, errorAt(x.pos(), "unsupported expression %s", x)
+}
+
+// aggregate compiles a call of an aggregate function.
+func (c *compiler) aggregate(x *callExpr) (compiled, error) {
+	if !c.aggregates {
+		return compiled{}, errorAt(x.p, "aggregate function %s not allowed in %s", x.fn, c.clause)
+	}
+	if c.inAggregate {
+		return compiled{}, errorAt(x.p, "aggregate function %s cannot be nested", x.fn)
+	}
+	if x.arg == nil {
+		return compiled{class: classNumber, eval: func(en *env) any {
+			return int64(len(en.group))
+		}}, nil
+	}
+	c.inAggregate = true
+	arg, err := c.compile(x.arg)
+	c.inAggregate = false
+	if err != nil {
+		return arg, err
+	}
+	// values returns the non-NULL values of the argument in the group.
+	values := func(en *env) []any {
+		var v []any
+		for _, row := range en.group {
+			if a := arg.eval(&env{row: row}); a != nil {
+				v = append(v, a)
+			}
+		}
+		return v
+	}
+	switch x.fn {
+	case "COUNT":
+		return compiled{class: classNumber, eval: func(en *env) any {
+			return int64(len(values(en)))
+		}}, nil
+	case "SUM", "AVG":
+		if arg.class != classNumber && arg.class != classAny {
+			return arg, errorAt(x.arg.pos(), "%s requires a number, found %s of type %v", x.fn, x.arg, arg.class)
+		}
+		avg := x.fn == "AVG"
+		return compiled{class: classNumber, eval: func(en *env) any {
+			return sumValues(values(en), avg)
+		}}, nil
+	}
+	if !arg.class.ordered() {
+		return arg, errorAt(x.arg.pos(), "%s requires an ordered value, found %s of type %v", x.fn, x.arg, arg.class)
+	}
+	sign := 1
+	if x.fn == "MAX" {
+		sign = -1
+	}
+	return compiled{class: arg.class, eval: func(en *env) any {
+		var best any
+		for _, v := range values(en) {
+			if best == nil || sign*orderValues(v, best) < 0 {
+				best = v
+			}
+		}
+		return best
+	}}, nil
+}
+
+// sumValues returns the sum of the numbers in v, or their mean if avg
+// is true. The sum of integers is an int64, any other result a float64.
+// It returns nil if v is empty.
+func sumValues(v []any, avg bool) any {
+	if len(v) == 0 {
+		return nil
+	}
+	var n int64
+	var f float64
+	float := avg
+	for _, e := range v {
+		switch e := e.(type) {
+		case int64:
+			n += e
+		case float64:
+			f += e
+			float = true
+		}
+	}
+	if !float {
+		return n
+	}
+	f += float64(n)
+	if avg {
+		return f / float64(len(v))
+	}
+	return f
+}
+
+// operands compiles the operands a and b of the operator expression x
+// and checks that they can be compared, and ordered if ordered is true.
+func (c *compiler) operands(x expr, ordered bool, a, b expr) (compiled, compiled, error) {
+	l, err := c.compile(a)
+	if err != nil {
+		return l, l, err
+	}
+	r, err := c.compile(b)
+	if err != nil {
+		return l, r, err
+	}
+	if !l.class.compatible(r.class) {
+		return l, r, errorAt(b.pos(), "cannot compare %s of type %v with %s of type %v", a, l.class, b, r.class)
+	}
+	if ordered {
+		for _, o := range []struct {
+			x expr
+			c class
+		}{{a, l.class}, {b, r.class}} {
+			if !o.c.ordered() {
+				return l, r, errorAt(o.x.pos(), "cannot order %s of type %v", o.x, o.c)
+			}
+		}
+	}
+	return l, r, nil
+}
+
+// compareOps are the tests of the comparison operators.
+//
+// Equality treats NULL as a value equal only to itself, so that
+// "x = NULL" tests for a missing value. An ordering with NULL is false.
+var compareOps = map[string]func(a, b any) bool{
+	"=": equalValues,
+	"!=": func(a, b any) bool {
+		return !equalValues(a, b)
+	},
+	"<": func(a, b any) bool {
+		c, ok := compareValues(a, b)
+		return ok && c < 0
+	},
+	"<=": lessOrEqual,
+	">": func(a, b any) bool {
+		c, ok := compareValues(a, b)
+		return ok && c > 0
+	},
+	">=": func(a, b any) bool {
+		c, ok := compareValues(a, b)
+		return ok && c >= 0
+	},
+}
+
+// lessOrEqual reports whether a is ordered before or equal to b.
+func lessOrEqual(a, b any) bool {
+	c, ok := compareValues(a, b)
+	return ok && c <= 0
+}
+
+// truth reports whether v is the boolean true.
+func truth(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// compareValues compares two normalized values. It reports false if
+// the values cannot be ordered, because one of them is NULL or their
+// types differ.
+func compareValues(a, b any) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return compare(a, b), true
+		case float64:
+			return compare(float64(a), b), true
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return compare(a, float64(b)), true
+		case float64:
+			return compare(a, b), true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return compare(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0, true
+			case b:
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+// compare returns -1, 0 or +1 depending on whether a is less than,
+// equal to or greater than b.
+func compare[T int64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// equalValues reports whether two normalized values are equal.
+func equalValues(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if c, ok := compareValues(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// orderValues compares two normalized values for sorting.
+// NULL is ordered before every other value.
+func orderValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	c, _ := compareValues(a, b)
+	return c
+}
+
+// normalize converts a field value to the representation used by
+// expressions: nil, bool, int64, float64, string or the value itself.
+func normalize(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+	return v.Interface()
+}
+
+// raw returns the value of a field as is, or nil for a missing value.
+func raw(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return v.Interface()
+}
+
+// splitPath splits a dotted field path into its names.
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// joinPath returns the first n names of the dotted field path.
+func joinPath(path string, n int) string {
+	return strings.Join(splitPath(path)[:n], ".")
+}
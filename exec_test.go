@@ -0,0 +1,247 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type address struct {
+	City string
+}
+
+type member struct {
+	Name    string
+	Age     int
+	Active  bool
+	Score   float64
+	Address *address
+}
+
+var members = &Query[member]{
+	{"Bob", 31, true, 7.5, &address{"Berlin"}},
+	{"Jenny", 26, false, 9, nil},
+	{"John", 42, true, 6, &address{"Paris"}},
+	{"Michael", 17, true, 9, &address{"Berlin"}},
+	{"Jane", 35, false, 8.25, &address{"Rome"}},
+}
+
+func memberNames(q *Query[member]) []string {
+	return Select(q, func(m member) string {
+		return m.Name
+	}).ToSlice()
+}
+
+func Test_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "empty query",
+			src:  "",
+			want: []string{"Bob", "Jenny", "John", "Michael", "Jane"},
+		},
+		{
+			name: "comparison",
+			src:  "WHERE Age > 30",
+			want: []string{"Bob", "John", "Jane"},
+		},
+		{
+			name: "mixed numbers",
+			src:  "WHERE Score >= 8.25 AND Age < 30.5",
+			want: []string{"Jenny", "Michael"},
+		},
+		{
+			name: "boolean field",
+			src:  "WHERE Active AND NOT Age < 18",
+			want: []string{"Bob", "John"},
+		},
+		{
+			name: "in",
+			src:  "WHERE Name IN ('Bob', 'Jane', 'Nobody')",
+			want: []string{"Bob", "Jane"},
+		},
+		{
+			name: "not in",
+			src:  "WHERE Age NOT IN (31, 42)",
+			want: []string{"Jenny", "Michael", "Jane"},
+		},
+		{
+			name: "like",
+			src:  "WHERE Name LIKE 'J_n%'",
+			want: []string{"Jenny", "Jane"},
+		},
+		{
+			name: "not like",
+			src:  "WHERE Name NOT LIKE '%n%'",
+			want: []string{"Bob", "Michael"},
+		},
+		{
+			name: "between",
+			src:  "WHERE Age BETWEEN 26 AND 35",
+			want: []string{"Bob", "Jenny", "Jane"},
+		},
+		{
+			name: "nested field",
+			src:  "WHERE Address.City = 'Berlin'",
+			want: []string{"Bob", "Michael"},
+		},
+		{
+			name: "null",
+			src:  "WHERE Address.City = NULL OR Address = NULL",
+			want: []string{"Jenny"},
+		},
+		{
+			name: "order by",
+			src:  "ORDER BY Score DESC, Name",
+			want: []string{"Jenny", "Michael", "Jane", "Bob", "John"},
+		},
+		{
+			name: "order by nulls first",
+			src:  "ORDER BY Address.City, Age DESC",
+			want: []string{"Jenny", "Bob", "Michael", "John", "Jane"},
+		},
+		{
+			name: "limit and offset",
+			src:  "WHERE Age > 18 ORDER BY Age LIMIT 2 OFFSET 1",
+			want: []string{"Bob", "Jane"},
+		},
+		{
+			name: "offset overflow",
+			src:  "OFFSET 10",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Run(members, tt.src)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if names := memberNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Run() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Compile_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		column int
+	}{
+		{
+			name:   "unknown field",
+			src:    "WHERE Agee > 3",
+			column: 7,
+		},
+		{
+			name:   "unknown nested field",
+			src:    "WHERE Address.Town = 'Rome'",
+			column: 7,
+		},
+		{
+			name:   "field of non-struct",
+			src:    "WHERE Name.First = 'Bob'",
+			column: 7,
+		},
+		{
+			name:   "type mismatch",
+			src:    "WHERE Age = 'old'",
+			column: 13,
+		},
+		{
+			name:   "type mismatch in list",
+			src:    "WHERE Age IN (1, 'two')",
+			column: 18,
+		},
+		{
+			name:   "non-boolean condition",
+			src:    "WHERE Age",
+			column: 7,
+		},
+		{
+			name:   "like on number",
+			src:    "WHERE Age LIKE '1%'",
+			column: 7,
+		},
+		{
+			name:   "unordered type",
+			src:    "ORDER BY Address",
+			column: 10,
+		},
+		{
+			name:   "duplicate column",
+			src:    "SELECT Name, Age AS Name",
+			column: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile[member](tt.src)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Compile() error = %v, want *ParseError", err)
+			}
+			if perr.Column != tt.column {
+				t.Errorf("Compile() error = %v, want column %d", err, tt.column)
+			}
+		})
+	}
+	if _, err := Compile[int]("WHERE x = 1"); err == nil {
+		t.Errorf("Compile[int]() error = nil, want error")
+	}
+}
+
+func TestProgram_Rows(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []map[string]any
+	}{
+		{
+			name: "columns",
+			src:  "SELECT Name, Age > 30 AS senior, Address.City AS city WHERE Score = 9",
+			want: []map[string]any{
+				{"Name": "Jenny", "senior": false, "city": nil},
+				{"Name": "Michael", "senior": false, "city": "Berlin"},
+			},
+		},
+		{
+			name: "star",
+			src:  "SELECT * WHERE Name = 'Bob'",
+			want: []map[string]any{
+				{"Name": "Bob", "Age": 31, "Active": true, "Score": 7.5, "Address": &address{"Berlin"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Compile[member](tt.src)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if got := p.Rows(members); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Program.Rows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Run_Pointers(t *testing.T) {
+	q := NewQuery([]*member{&(*members)[0], &(*members)[1], nil})
+	got, err := Run(q, "WHERE Age < 30")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(*got) != 1 || got.First().Name != "Jenny" {
+		t.Errorf("Run() = %v, want [Jenny]", got)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// expr is a node of the syntax tree of a query expression.
+type expr interface {
+	// pos returns the 0-based offset of the node in the query text.
+	pos() int
+	// String returns the node in the syntax of the query language.
+	String() string
+}
+
+// identExpr is a reference to a field.
+type identExpr struct {
+	p    int
+	name string
+}
+
+// literalExpr is a constant: nil, a bool, an int64, a float64 or a string.
+type literalExpr struct {
+	p     int
+	value any
+}
+
+// notExpr is a logical negation.
+type notExpr struct {
+	p int
+	x expr
+}
+
+// logicalExpr is a conjunction or disjunction.
+type logicalExpr struct {
+	p    int
+	op   string
+	x, y expr
+}
+
+// compareExpr is a binary comparison.
+type compareExpr struct {
+	p    int
+	op   string
+	x, y expr
+}
+
+// inExpr tests membership of a value in a list.
+type inExpr struct {
+	p    int
+	x    expr
+	list []expr
+	not  bool
+}
+
+// likeExpr matches a string against a pattern.
+type likeExpr struct {
+	p       int
+	x       expr
+	pattern string
+	re      *regexp.Regexp
+	not     bool
+}
+
+// betweenExpr tests whether a value lies within an inclusive range.
+type betweenExpr struct {
+	p         int
+	x, lo, hi expr
+	not       bool
+}
+
+// callExpr is a call of an aggregate function.
+// The argument of COUNT(*) is nil.
+type callExpr struct {
+	p   int
+	fn  string
+	arg expr
+}
+
+// aggregates are the names of the aggregate functions.
+var aggregates = map[string]bool{
+	"AVG":   true,
+	"COUNT": true,
+	"MAX":   true,
+	"MIN":   true,
+	"SUM":   true,
+}
+
+func (x *identExpr) pos() int   { return x.p }
+func (x *literalExpr) pos() int { return x.p }
+func (x *notExpr) pos() int     { return x.p }
+func (x *logicalExpr) pos() int { return x.p }
+func (x *compareExpr) pos() int { return x.p }
+func (x *inExpr) pos() int      { return x.p }
+func (x *likeExpr) pos() int    { return x.p }
+func (x *betweenExpr) pos() int { return x.p }
+func (x *callExpr) pos() int    { return x.p }
+
+func (x *identExpr) String() string {
+	return x.name
+}
+
+func (x *literalExpr) String() string {
+	return literal(x.value)
+}
+
+func (x *notExpr) String() string {
+	return "NOT " + paren(x.x)
+}
+
+func (x *logicalExpr) String() string {
+	return operand(x.x, x.op) + " " + x.op + " " + operand(x.y, x.op)
+}
+
+func (x *compareExpr) String() string {
+	return x.x.String() + " " + x.op + " " + x.y.String()
+}
+
+func (x *inExpr) String() string {
+	list := make([]string, len(x.list))
+	for i, e := range x.list {
+		list[i] = e.String()
+	}
+	return x.x.String() + negated(x.not) + " IN (" + strings.Join(list, ", ") + ")"
+}
+
+func (x *likeExpr) String() string {
+	return x.x.String() + negated(x.not) + " LIKE " + quote(x.pattern)
+}
+
+func (x *betweenExpr) String() string {
+	return x.x.String() + negated(x.not) + " BETWEEN " + x.lo.String() + " AND " + x.hi.String()
+}
+
+func (x *callExpr) String() string {
+	if x.arg == nil {
+		return x.fn + "(*)"
+	}
+	return x.fn + "(" + x.arg.String() + ")"
+}
+
+// newLikeExpr returns a LIKE expression with its pattern compiled
+// into an anchored regular expression.
+func newLikeExpr(p int, x expr, pattern string, not bool) *likeExpr {
+	var b strings.Builder
+	b.WriteString(`^(?s:`)
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(`.*`)
+		case '_':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`)$`)
+	return &likeExpr{p: p, x: x, pattern: pattern, re: regexp.MustCompile(b.String()), not: not}
+}
+
+// hasAggregate reports whether x contains a call of an aggregate function.
+func hasAggregate(x expr) bool {
+	switch x := x.(type) {
+	case *callExpr:
+		return true
+	case *notExpr:
+		return hasAggregate(x.x)
+	case *logicalExpr:
+		return hasAggregate(x.x) || hasAggregate(x.y)
+	case *compareExpr:
+		return hasAggregate(x.x) || hasAggregate(x.y)
+	case *inExpr:
+		return hasAggregate(x.x) || slices.ContainsFunc(x.list, hasAggregate)
+	case *likeExpr:
+		return hasAggregate(x.x)
+	case *betweenExpr:
+		return hasAggregate(x.x) || hasAggregate(x.lo) || hasAggregate(x.hi)
+	}
+	return false
+}
+
+// literal returns the value v in the syntax of the query language.
+func literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case string:
+		return quote(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEIN") {
+			// Keep the literal a float when it is parsed again.
+			s += ".0"
+		}
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// negated returns " NOT" if not is true.
+func negated(not bool) string {
+	if not {
+		return " NOT"
+	}
+	return ""
+}
+
+// paren returns x, parenthesized if it is a logical expression.
+func paren(x expr) string {
+	if _, ok := x.(*logicalExpr); ok {
+		return "(" + x.String() + ")"
+	}
+	return x.String()
+}
+
+// operand returns x as an operand of the logical operator op,
+// parenthesized if it binds less tightly than op.
+func operand(x expr, op string) string {
+	if l, ok := x.(*logicalExpr); ok && l.op != op {
+		return "(" + x.String() + ")"
+	}
+	return x.String()
+}
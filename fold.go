@@ -0,0 +1,78 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+// Aggregate applies f to each element in the Query and returns the
+// accumulated result.
+//
+// Unlike Query.Fold, the accumulator may have a different type than the
+// elements. The accumulation starts with seed, and f is called with the
+// accumulated result and the current element. A nil f returns seed.
+func Aggregate[E, A any](q *Query[E], seed A, f func(A, E) A) A {
+	result := seed
+	if f == nil {
+		return result
+	}
+	for _, e := range *q {
+		result = f(result, e)
+	}
+	return result
+}
+
+// Reduce combines the elements of the Query with f, using the first
+// element as the initial value.
+//
+// The second result is false if the Query is empty or f is nil.
+func (q *Query[E]) Reduce(f func(E, E) E) (E, bool) {
+	var result E
+	if len(*q) < 1 || f == nil {
+		return result, false
+	}
+	result = (*q)[0]
+	for _, e := range (*q)[1:] {
+		result = f(result, e)
+	}
+	return result, true
+}
+
+// Scan applies f to each element in the Query like Aggregate and
+// returns every intermediate accumulated result.
+//
+// The element at index i of the result is the accumulator after the
+// element at index i of the Query has been combined, so a running total
+// of [1 2 3] starting at 0 is [1 3 6]. A nil f yields an empty Query.
+func Scan[E, A any](q *Query[E], seed A, f func(A, E) A) *Query[A] {
+	if f == nil {
+		return &Query[A]{}
+	}
+	result := Query[A](make([]A, len(*q)))
+	acc := seed
+	for i, e := range *q {
+		acc = f(acc, e)
+		result[i] = acc
+	}
+	return &result
+}
+
+// FoldUntil applies f to each element in the Query like Aggregate,
+// but lets f stop the accumulation early.
+//
+// The function f returns the new accumulated result and whether to
+// continue with the next element. The result returned together with
+// false is kept, and no further elements are visited.
+// A nil f returns seed.
+func FoldUntil[E, A any](q *Query[E], seed A, f func(A, E) (A, bool)) A {
+	result := seed
+	if f == nil {
+		return result
+	}
+	for _, e := range *q {
+		var more bool
+		if result, more = f(result, e); !more {
+			break
+		}
+	}
+	return result
+}
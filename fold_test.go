@@ -0,0 +1,164 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_Aggregate(t *testing.T) {
+	type args struct {
+		q    *Query[int]
+		seed string
+		f    func(string, int) string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "nil function",
+			args: args{
+				q:    &Query[int]{1, 2},
+				seed: "seed",
+			},
+			want: "seed",
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q:    &Query[int]{},
+				seed: "seed",
+				f: func(acc string, e int) string {
+					return acc + strconv.Itoa(e)
+				},
+			},
+			want: "seed",
+		},
+		{
+			name: "different accumulator type",
+			args: args{
+				q:    &Query[int]{1, 2, 3},
+				seed: ">",
+				f: func(acc string, e int) string {
+					return acc + strconv.Itoa(e)
+				},
+			},
+			want: ">123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Aggregate(tt.args.q, tt.args.seed, tt.args.f); got != tt.want {
+				t.Errorf("Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Reduce(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	tests := []struct {
+		name   string
+		q      *Query[int]
+		f      func(int, int) int
+		want   int
+		wantOk bool
+	}{
+		{
+			name:   "nil function",
+			q:      &Query[int]{1, 2},
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name:   "empty slice",
+			q:      &Query[int]{},
+			f:      add,
+			want:   0,
+			wantOk: false,
+		},
+		{
+			name:   "single element",
+			q:      &Query[int]{7},
+			f:      add,
+			want:   7,
+			wantOk: true,
+		},
+		{
+			name:   "non-empty slice",
+			q:      &Query[int]{1, 2, 3},
+			f:      add,
+			want:   6,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.q.Reduce(tt.f)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Query.Reduce() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	add := func(acc float64, e int) float64 { return acc + float64(e) }
+	tests := []struct {
+		name string
+		q    *Query[int]
+		f    func(float64, int) float64
+		want *Query[float64]
+	}{
+		{
+			name: "nil function",
+			q:    &Query[int]{1, 2},
+			want: &Query[float64]{},
+		},
+		{
+			name: "empty slice",
+			q:    &Query[int]{},
+			f:    add,
+			want: &Query[float64]{},
+		},
+		{
+			name: "running total",
+			q:    &Query[int]{1, 2, 3},
+			f:    add,
+			want: &Query[float64]{1, 3, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scan(tt.q, 0, tt.f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FoldUntil(t *testing.T) {
+	visited := 0
+	budget := func(acc, e int) (int, bool) {
+		visited++
+		if acc+e > 10 {
+			return acc, false
+		}
+		return acc + e, true
+	}
+	if got := FoldUntil(&Query[int]{4, 3, 2, 5, 1}, 0, budget); got != 9 {
+		t.Errorf("FoldUntil() = %v, want %v", got, 9)
+	}
+	if visited != 4 {
+		t.Errorf("FoldUntil() visited %v elements, want %v", visited, 4)
+	}
+	if got := FoldUntil[int, int](&Query[int]{1}, 5, nil); got != 5 {
+		t.Errorf("FoldUntil(nil) = %v, want %v", got, 5)
+	}
+}
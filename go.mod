@@ -1,9 +1,10 @@
 module github.com/dmundt/sliceql
 
-go 1.21.0
+go 1.23.0
+
+require golang.org/x/tools v0.26.0
 
 require (
-	github.com/yuin/goldmark v1.4.13 // indirect
-	golang.org/x/mod v0.14.0 // indirect
-	golang.org/x/tools v0.16.0 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
 )
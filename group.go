@@ -0,0 +1,107 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import "fmt"
+
+// A Group is a key together with the elements that share it.
+type Group[K comparable, E any] struct {
+	Key      K
+	Elements *Query[E]
+}
+
+// String returns a string representation of the Group.
+func (g Group[K, E]) String() string {
+	return fmt.Sprintf("%v: %v", g.Key, g.Elements)
+}
+
+// GroupBy groups the elements of the Query by the key returned from key.
+//
+// The groups appear in the order in which their keys were first seen,
+// and the elements of each group keep their order from the source.
+//
+// The function returns a pointer to a new Query of groups. The source
+// Query is not modified. A nil key yields an empty Query.
+func GroupBy[E any, K comparable](q *Query[E], key func(E) K) *Query[Group[K, E]] {
+	return GroupByElement(q, key, func(e E) E {
+		return e
+	})
+}
+
+// GroupByElement groups the elements of the Query by the key returned
+// from key and projects each element with elem before adding it to its
+// group.
+//
+// The groups appear in the order in which their keys were first seen.
+// A nil key or elem yields an empty Query.
+func GroupByElement[E any, K comparable, V any](q *Query[E], key func(E) K, elem func(E) V) *Query[Group[K, V]] {
+	result := Query[Group[K, V]](make([]Group[K, V], 0))
+	if key == nil || elem == nil {
+		return &result
+	}
+	index := make(map[K]int)
+	for _, e := range *q {
+		k := key(e)
+		i, ok := index[k]
+		if !ok {
+			i = len(result)
+			index[k] = i
+			result = append(result, Group[K, V]{Key: k, Elements: &Query[V]{}})
+		}
+		*result[i].Elements = append(*result[i].Elements, elem(e))
+	}
+	return &result
+}
+
+// GroupByResult groups the elements of the Query by the key returned
+// from key and reduces every group to a single value with result.
+//
+// Every group is collected into a Query before result is called, once
+// per group, in the order in which the keys were first seen. To reduce
+// the groups while grouping, without keeping their elements, use
+// GroupByAggregate. A nil key or result yields an empty Query.
+func GroupByResult[E any, K comparable, R any](q *Query[E], key func(E) K, result func(K, *Query[E]) R) *Query[R] {
+	if result == nil {
+		return &Query[R]{}
+	}
+	return Select(GroupBy(q, key), func(g Group[K, E]) R {
+		return result(g.Key, g.Elements)
+	})
+}
+
+// GroupByAggregate groups the elements of the Query by the key returned
+// from key and reduces every group to a single value in one pass.
+//
+// Like Aggregate, the accumulator of every group starts with seed and f
+// is called with the accumulated result and the next element of the
+// group. The elements are not kept, so counting or summing the groups
+// takes memory for the accumulators only. Finally result is called once
+// per group with its key and accumulator, in the order in which the keys
+// were first seen. A nil function yields an empty Query.
+func GroupByAggregate[E any, K comparable, A, R any](q *Query[E], key func(E) K, seed A, f func(A, E) A, result func(K, A) R) *Query[R] {
+	if key == nil || f == nil || result == nil {
+		return &Query[R]{}
+	}
+	var (
+		keys  []K
+		accs  []A
+		index = make(map[K]int)
+	)
+	for _, e := range *q {
+		k := key(e)
+		i, ok := index[k]
+		if !ok {
+			i = len(keys)
+			index[k] = i
+			keys, accs = append(keys, k), append(accs, seed)
+		}
+		accs[i] = f(accs[i], e)
+	}
+	r := Query[R](make([]R, len(keys)))
+	for i, k := range keys {
+		r[i] = result(k, accs[i])
+	}
+	return &r
+}
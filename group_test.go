@@ -0,0 +1,187 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_GroupBy(t *testing.T) {
+	type args struct {
+		q   *Query[int]
+		key func(int) int
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[Group[int, int]]
+	}{
+		{
+			name: "nil key",
+			args: args{
+				q:   &Query[int]{1, 2, 3},
+				key: nil,
+			},
+			want: &Query[Group[int, int]]{},
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q: &Query[int]{},
+				key: func(e int) int {
+					return e % 3
+				},
+			},
+			want: &Query[Group[int, int]]{},
+		},
+		{
+			name: "first-seen key order",
+			args: args{
+				q: &Query[int]{5, 1, 2, 3, 4, 6},
+				key: func(e int) int {
+					return e % 3
+				},
+			},
+			want: &Query[Group[int, int]]{
+				{Key: 2, Elements: &Query[int]{5, 2}},
+				{Key: 1, Elements: &Query[int]{1, 4}},
+				{Key: 0, Elements: &Query[int]{3, 6}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupBy(tt.args.q, tt.args.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GroupByElement(t *testing.T) {
+	q := &Query[Person]{
+		{"Bob", 31},
+		{"Jenny", 26},
+		{"John", 31},
+	}
+	got := GroupByElement(q, func(p Person) int {
+		return p.Age
+	}, func(p Person) string {
+		return p.Name
+	})
+	want := &Query[Group[int, string]]{
+		{Key: 31, Elements: &Query[string]{"Bob", "John"}},
+		{Key: 26, Elements: &Query[string]{"Jenny"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByElement() = %v, want %v", got, want)
+	}
+}
+
+func Test_GroupByResult(t *testing.T) {
+	type count struct {
+		Key int
+		N   int
+	}
+	type args struct {
+		q      *Query[int]
+		key    func(int) int
+		result func(int, *Query[int]) count
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[count]
+	}{
+		{
+			name: "nil result",
+			args: args{
+				q: &Query[int]{1, 2, 3},
+				key: func(e int) int {
+					return e % 2
+				},
+				result: nil,
+			},
+			want: &Query[count]{},
+		},
+		{
+			name: "count per group",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				key: func(e int) int {
+					return e % 2
+				},
+				result: func(k int, g *Query[int]) count {
+					return count{k, g.Count(func(int) bool { return true })}
+				},
+			},
+			want: &Query[count]{{1, 3}, {0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupByResult(tt.args.q, tt.args.key, tt.args.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupByResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_GroupByAggregate(t *testing.T) {
+	type total struct {
+		Key      int
+		N, Total int
+	}
+	parity := func(e int) int {
+		return e % 2
+	}
+	sum := func(acc total, e int) total {
+		return total{N: acc.N + 1, Total: acc.Total + e}
+	}
+	withKey := func(k int, acc total) total {
+		acc.Key = k
+		return acc
+	}
+	q := &Query[int]{1, 2, 3, 4, 5}
+	want := &Query[total]{{1, 3, 9}, {0, 2, 6}}
+	if got := GroupByAggregate(q, parity, total{}, sum, withKey); !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupByAggregate() = %v, want %v", got, want)
+	}
+	if got := GroupByAggregate(&Query[int]{}, parity, total{}, sum, withKey); len(*got) != 0 {
+		t.Errorf("GroupByAggregate() on empty Query = %v, want []", got)
+	}
+	if got := GroupByAggregate(q, parity, total{}, nil, withKey); len(*got) != 0 {
+		t.Errorf("GroupByAggregate(nil) = %v, want []", got)
+	}
+
+	// The accumulators see the elements in one pass, in source order.
+	calls := 0
+	GroupByAggregate(q, parity, 0, func(acc, e int) int {
+		if calls++; (*q)[calls-1] != e {
+			t.Errorf("GroupByAggregate() call %d got element %d, want %d", calls, e, (*q)[calls-1])
+		}
+		return acc
+	}, func(k, acc int) int { return acc })
+	if calls != len(*q) {
+		t.Errorf("GroupByAggregate() called f %d times, want %d", calls, len(*q))
+	}
+}
+
+func Test_GroupBy_Compose(t *testing.T) {
+	groups := GroupBy(&Query[int]{1, 2, 3, 4, 5, 6, 7}, func(e int) int {
+		return e % 3
+	}).Where(func(g Group[int, int]) bool {
+		return len(*g.Elements) > 2
+	}).Sort(func(g1, g2 Group[int, int]) bool {
+		return g1.Key < g2.Key
+	})
+	if got := len(*groups); got != 1 {
+		t.Fatalf("len(groups) = %v, want 1", got)
+	}
+	if got := groups.First().Key; got != 1 {
+		t.Errorf("groups.First().Key = %v, want 1", got)
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A tracker counts the mutations of a Query that has indexes.
+type tracker struct {
+	gen atomic.Uint64
+	// refs is the number of open indexes on the Query.
+	// It is guarded by trackersMu.
+	refs int
+}
+
+var (
+	trackersMu sync.Mutex
+	// trackers maps the Queries with open indexes to their trackers.
+	trackers sync.Map // map[any]*tracker
+	// indexed is the number of trackers, which lets touch skip the
+	// lookup in trackers while no index is open.
+	indexed atomic.Int64
+)
+
+// track returns the tracker of the Query q and adds a reference to it.
+func track(q any) *tracker {
+	trackersMu.Lock()
+	defer trackersMu.Unlock()
+	t, loaded := trackers.LoadOrStore(q, &tracker{})
+	if !loaded {
+		indexed.Add(1)
+	}
+	t.(*tracker).refs++
+	return t.(*tracker)
+}
+
+// untrack removes a reference to the tracker of the Query q.
+func untrack(q any) {
+	trackersMu.Lock()
+	defer trackersMu.Unlock()
+	if t, ok := trackers.Load(q); ok {
+		t := t.(*tracker)
+		if t.refs--; t.refs == 0 {
+			trackers.Delete(q)
+			indexed.Add(-1)
+		}
+	}
+}
+
+// touch records that the elements of q have changed, so that the
+// indexes built on q are rebuilt before their next lookup.
+func (q *Query[E]) touch() {
+	if indexed.Load() == 0 {
+		return
+	}
+	if t, ok := trackers.Load(q); ok {
+		t.(*tracker).gen.Add(1)
+	}
+}
+
+// index is the state shared by the index types: the indexed Query and
+// the generation of the Query the index was built for.
+type index[E any] struct {
+	mu     sync.RWMutex
+	q      *Query[E]
+	t      *tracker
+	gen    uint64
+	stale  bool
+	closed atomic.Bool
+}
+
+// open starts tracking the mutations of q and builds the index with
+// the function build.
+//
+// The build function is passed to every method that may rebuild the
+// index, rather than stored in it, so that an index does not refer to
+// itself and its finalizer can run.
+func (ix *index[E]) open(q *Query[E], build func()) {
+	ix.q, ix.t = q, track(q)
+	ix.gen = ix.t.gen.Load()
+	build()
+}
+
+// read runs f with the read lock held, after rebuilding the index if
+// the Query has changed since it was built.
+//
+// The function f reports whether the index matched the elements it
+// looked up. If it did not, the elements were changed without the
+// index noticing, so the index is rebuilt and f runs once more.
+func (ix *index[E]) read(build func(), f func() bool) {
+	if ix.readLocked(build, f) {
+		return
+	}
+	ix.rebuild(build, true)
+	ix.readLocked(build, f)
+}
+
+// readLocked runs f with the read lock held, after rebuilding the index
+// if the Query has changed since it was built, and returns its result.
+func (ix *index[E]) readLocked(build func(), f func() bool) bool {
+	ix.mu.RLock()
+	if ix.stale || ix.t.gen.Load() != ix.gen {
+		ix.mu.RUnlock()
+		ix.rebuild(build, false)
+		ix.mu.RLock()
+	}
+	defer ix.mu.RUnlock()
+	return f()
+}
+
+// rebuild rebuilds the index if it is stale, or if force is true.
+func (ix *index[E]) rebuild(build func(), force bool) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if gen := ix.t.gen.Load(); force || ix.stale || gen != ix.gen {
+		ix.gen, ix.stale = gen, false
+		build()
+	}
+}
+
+// Invalidate marks the index as stale, so that it is rebuilt before
+// the next lookup.
+//
+// Indexes notice the changes made by the methods of Query that modify
+// it, such as Each, Sort, Skip, Take and Where, by themselves, when
+// they are called on the indexed *Query. Changes made directly to the
+// elements, as in (*q)[i] = e, or through another Query that shares
+// them, such as one created by NewQuery from the same slice, must be
+// announced with Invalidate.
+func (ix *index[E]) Invalidate() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.stale = true
+}
+
+// Close stops tracking the changes of the Query. The index must not be
+// used after Close. Closing an index more than once has no effect.
+//
+// Close is required: while an index is open, every change to any Query
+// looks up whether it is indexed, and the indexed Query stays
+// reachable. An index dropped without Close is closed by a finalizer
+// only when the garbage collector gets to it, which may be never.
+func (ix *index[E]) Close() {
+	if !ix.closed.Swap(true) {
+		untrack(ix.q)
+	}
+}
+
+// A HashIndex maps the keys of the elements of a Query to their
+// positions, to find the elements with a given key without scanning
+// the Query.
+//
+// A HashIndex follows the changes made to the Query by its methods and
+// is rebuilt on the next lookup after such a change. Since the changes
+// are tracked by the identity of the indexed *Query, changes made by
+// other means go unnoticed; see Invalidate. As a safeguard, lookups
+// check the keys of the elements they find and rebuild the index if one
+// does not match, so that they never return an element with another
+// key. An element that gains the key is only found after Invalidate or
+// Rebuild, however.
+//
+// Lookups are safe for concurrent use, but not concurrently with
+// changes to the Query. An index must be closed once it is no longer
+// needed.
+type HashIndex[E any, K comparable] struct {
+	index[E]
+	key func(E) K
+	m   map[K][]int
+}
+
+// NewHashIndex builds a hash index on the elements of q by key.
+func NewHashIndex[E any, K comparable](q *Query[E], key func(E) K) *HashIndex[E, K] {
+	ix := &HashIndex[E, K]{key: key}
+	ix.open(q, ix.build)
+	runtime.SetFinalizer(ix, (*HashIndex[E, K]).Close)
+	return ix
+}
+
+func (ix *HashIndex[E, K]) build() {
+	ix.m = make(map[K][]int)
+	for i, e := range *ix.q {
+		k := ix.key(e)
+		ix.m[k] = append(ix.m[k], i)
+	}
+}
+
+// Rebuild rebuilds the index from the current elements of the Query.
+func (ix *HashIndex[E, K]) Rebuild() {
+	ix.rebuild(ix.build, true)
+}
+
+// Lookup returns a new Query with the elements with key k, in the order
+// of the indexed Query.
+func (ix *HashIndex[E, K]) Lookup(k K) *Query[E] {
+	var result Query[E]
+	ix.read(ix.build, func() bool {
+		var ok bool
+		result, ok = collectAt(ix.q, ix.m[k], ix.has(k))
+		return ok
+	})
+	return &result
+}
+
+// Contains reports whether an element has key k.
+func (ix *HashIndex[E, K]) Contains(k K) bool {
+	return ix.Index(k) >= 0
+}
+
+// Count returns the number of elements with key k.
+func (ix *HashIndex[E, K]) Count(k K) int {
+	n := 0
+	ix.read(ix.build, func() bool {
+		pos := ix.m[k]
+		n = len(pos)
+		return matchAt(ix.q, pos, ix.has(k))
+	})
+	return n
+}
+
+// Index returns the position of the first element with key k,
+// or -1 if there is none.
+func (ix *HashIndex[E, K]) Index(k K) int {
+	i := -1
+	ix.read(ix.build, func() bool {
+		pos := ix.m[k]
+		if len(pos) == 0 {
+			i = -1
+			return true
+		}
+		i = pos[0]
+		return matchAt(ix.q, pos[:1], ix.has(k))
+	})
+	return i
+}
+
+// has returns a function that reports whether an element has key k.
+func (ix *HashIndex[E, K]) has(k K) func(int, E) bool {
+	return func(_ int, e E) bool {
+		return ix.key(e) == k
+	}
+}
+
+// A SortedIndex orders the positions of the elements of a Query by
+// their keys, to find the elements with keys in a range without
+// scanning the Query.
+//
+// Lookups return the elements in the order of their keys, and elements
+// with equal keys in the order of the indexed Query. A SortedIndex
+// follows the changes to the Query like a HashIndex, and its lookups
+// check the keys of the elements they find in the same way.
+type SortedIndex[E any, K cmp.Ordered] struct {
+	index[E]
+	key  func(E) K
+	keys []K
+	pos  []int
+}
+
+// NewSortedIndex builds a sorted index on the elements of q by key.
+func NewSortedIndex[E any, K cmp.Ordered](q *Query[E], key func(E) K) *SortedIndex[E, K] {
+	ix := &SortedIndex[E, K]{key: key}
+	ix.open(q, ix.build)
+	runtime.SetFinalizer(ix, (*SortedIndex[E, K]).Close)
+	return ix
+}
+
+func (ix *SortedIndex[E, K]) build() {
+	n := len(*ix.q)
+	keys := make([]K, n)
+	pos := make([]int, n)
+	for i, e := range *ix.q {
+		keys[i], pos[i] = ix.key(e), i
+	}
+	sort.Stable(byKey[K]{keys, pos})
+	ix.keys, ix.pos = keys, pos
+}
+
+// Rebuild rebuilds the index from the current elements of the Query.
+func (ix *SortedIndex[E, K]) Rebuild() {
+	ix.rebuild(ix.build, true)
+}
+
+// byKey sorts positions by their keys.
+type byKey[K cmp.Ordered] struct {
+	keys []K
+	pos  []int
+}
+
+func (s byKey[K]) Len() int           { return len(s.keys) }
+func (s byKey[K]) Less(i, j int) bool { return cmp.Less(s.keys[i], s.keys[j]) }
+func (s byKey[K]) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.pos[i], s.pos[j] = s.pos[j], s.pos[i]
+}
+
+// Eq returns a new Query with the elements with key k.
+func (ix *SortedIndex[E, K]) Eq(k K) *Query[E] {
+	return ix.Between(k, k)
+}
+
+// Between returns a new Query with the elements with keys from lo to
+// hi inclusive.
+func (ix *SortedIndex[E, K]) Between(lo, hi K) *Query[E] {
+	return ix.rangeOf(func(k K) bool {
+		return cmp.Compare(k, lo) >= 0
+	}, func(k K) bool {
+		return cmp.Compare(k, hi) > 0
+	})
+}
+
+// Lt returns a new Query with the elements with keys less than k.
+func (ix *SortedIndex[E, K]) Lt(k K) *Query[E] {
+	return ix.rangeOf(nil, func(x K) bool { return cmp.Compare(x, k) >= 0 })
+}
+
+// Le returns a new Query with the elements with keys less than or
+// equal to k.
+func (ix *SortedIndex[E, K]) Le(k K) *Query[E] {
+	return ix.rangeOf(nil, func(x K) bool { return cmp.Compare(x, k) > 0 })
+}
+
+// Gt returns a new Query with the elements with keys greater than k.
+func (ix *SortedIndex[E, K]) Gt(k K) *Query[E] {
+	return ix.rangeOf(func(x K) bool { return cmp.Compare(x, k) > 0 }, nil)
+}
+
+// Ge returns a new Query with the elements with keys greater than or
+// equal to k.
+func (ix *SortedIndex[E, K]) Ge(k K) *Query[E] {
+	return ix.rangeOf(func(x K) bool { return cmp.Compare(x, k) >= 0 }, nil)
+}
+
+// Prefix returns a new Query with the elements of the sorted index ix
+// whose keys start with prefix.
+func Prefix[E any, K ~string](ix *SortedIndex[E, K], prefix K) *Query[E] {
+	return ix.rangeOf(func(k K) bool {
+		return k >= prefix
+	}, func(k K) bool {
+		return k > prefix && !strings.HasPrefix(string(k), string(prefix))
+	})
+}
+
+// rangeOf returns the elements whose keys lie in the range from the
+// first key satisfying from, or the smallest key if from is nil, to the
+// last key before the first one satisfying to, or the largest key if to
+// is nil. Both from and to must be monotonic in the keys.
+func (ix *SortedIndex[E, K]) rangeOf(from, to func(K) bool) *Query[E] {
+	var result Query[E]
+	ix.read(ix.build, func() bool {
+		i, j := 0, len(ix.keys)
+		if from != nil {
+			i = sort.Search(len(ix.keys), func(n int) bool { return from(ix.keys[n]) })
+		}
+		if to != nil {
+			j = i + sort.Search(len(ix.keys)-i, func(n int) bool { return to(ix.keys[i+n]) })
+		}
+		keys := ix.keys[i:j]
+		var ok bool
+		result, ok = collectAt(ix.q, ix.pos[i:j], func(n int, e E) bool {
+			return cmp.Compare(ix.key(e), keys[n]) == 0
+		})
+		return ok
+	})
+	return &result
+}
+
+// collectAt returns the elements of q at the positions pos. It reports
+// false if a position is out of range or match, called with the index
+// of the position in pos, rejects its element.
+func collectAt[E any](q *Query[E], pos []int, match func(int, E) bool) (Query[E], bool) {
+	if !matchAt(q, pos, match) {
+		return Query[E]{}, false
+	}
+	result := make(Query[E], len(pos))
+	for i, p := range pos {
+		result[i] = (*q)[p]
+	}
+	return slices.Clip(result), true
+}
+
+// matchAt reports whether the positions pos are in range of q and
+// match accepts their elements, as collectAt does.
+func matchAt[E any](q *Query[E], pos []int, match func(int, E) bool) bool {
+	for i, p := range pos {
+		if p >= len(*q) || !match(i, (*q)[p]) {
+			return false
+		}
+	}
+	return true
+}
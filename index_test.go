@@ -0,0 +1,262 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func memberAgeOf(m member) int {
+	return m.Age
+}
+
+func memberActive(m member) bool {
+	return m.Active
+}
+
+func TestHashIndex(t *testing.T) {
+	q := members.Clone()
+	ix := NewHashIndex(q, memberActive)
+	defer ix.Close()
+	if got, want := memberNames(ix.Lookup(true)), []string{"Bob", "John", "Michael"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HashIndex.Lookup(true) = %v, want %v", got, want)
+	}
+	if got := ix.Index(false); got != 1 {
+		t.Errorf("HashIndex.Index(false) = %d, want 1", got)
+	}
+	if got := ix.Count(false); got != 2 {
+		t.Errorf("HashIndex.Count(false) = %d, want 2", got)
+	}
+
+	names := NewHashIndex(q, func(m member) string { return m.Name })
+	defer names.Close()
+	if !names.Contains("Jane") || names.Contains("Nobody") {
+		t.Errorf("HashIndex.Contains() = %t, %t, want true, false", names.Contains("Jane"), names.Contains("Nobody"))
+	}
+	if got := names.Lookup("Nobody"); len(*got) != 0 {
+		t.Errorf("HashIndex.Lookup(Nobody) = %v, want []", got)
+	}
+	if got := names.Index("Nobody"); got != -1 {
+		t.Errorf("HashIndex.Index(Nobody) = %d, want -1", got)
+	}
+}
+
+func TestHashIndex_Rebuild(t *testing.T) {
+	byAge := func(a, b member) bool {
+		return a.Age < b.Age
+	}
+	tests := []struct {
+		name   string
+		mutate func(q *Query[member])
+		want   []string
+		index  int
+	}{
+		{
+			name:   "where",
+			mutate: func(q *Query[member]) { q.Where(func(m member) bool { return m.Age > 30 }) },
+			want:   []string{"Bob", "John"},
+			index:  0,
+		},
+		{
+			name:   "sort",
+			mutate: func(q *Query[member]) { q.Sort(byAge) },
+			want:   []string{"Michael", "Bob", "John"},
+			index:  0,
+		},
+		{
+			name:   "skip",
+			mutate: func(q *Query[member]) { q.Skip(3) },
+			want:   []string{"Michael"},
+			index:  0,
+		},
+		{
+			name:   "take",
+			mutate: func(q *Query[member]) { q.Take(2) },
+			want:   []string{"Bob"},
+			index:  0,
+		},
+		{
+			name:   "reverse",
+			mutate: func(q *Query[member]) { q.Reverse() },
+			want:   []string{"Michael", "John", "Bob"},
+			index:  1,
+		},
+		{
+			name: "each",
+			mutate: func(q *Query[member]) {
+				q.Each(func(m member) member {
+					m.Active = !m.Active
+					return m
+				})
+			},
+			want:  []string{"Jenny", "Jane"},
+			index: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := members.Clone()
+			ix := NewHashIndex(q, memberActive)
+			defer ix.Close()
+			_ = ix.Lookup(true)
+			tt.mutate(q)
+			if got := memberNames(ix.Lookup(true)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HashIndex.Lookup(true) = %v, want %v", got, tt.want)
+			}
+			if got := ix.Index(true); got != tt.index {
+				t.Errorf("HashIndex.Index(true) = %d, want %d", got, tt.index)
+			}
+		})
+	}
+}
+
+func TestHashIndex_Invalidate(t *testing.T) {
+	q := members.Clone()
+	ix := NewHashIndex(q, memberActive)
+	defer ix.Close()
+	(*q)[0].Active = false
+	ix.Invalidate()
+	if got, want := memberNames(ix.Lookup(false)), []string{"Bob", "Jenny", "Jane"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HashIndex.Lookup(false) = %v, want %v", got, want)
+	}
+	(*q)[1].Active = true
+	ix.Rebuild()
+	if got, want := ix.Count(false), 2; got != want {
+		t.Errorf("HashIndex.Count(false) = %d, want %d", got, want)
+	}
+}
+
+func TestIndex_Aliased(t *testing.T) {
+	// Changes made through another Query over the same elements are not
+	// tracked, but lookups never return elements with another key.
+	isEven := func(e int) bool {
+		return e%2 == 0
+	}
+	q := &Query[int]{1, 2, 3, 4}
+	ix := NewHashIndex(q, isEven)
+	defer ix.Close()
+	sorted := NewSortedIndex(q, identity[int])
+	defer sorted.Close()
+	NewQuery(q.ToSlice()).Reverse()
+	if got, want := ix.Lookup(true).ToSlice(), []int{4, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HashIndex.Lookup(true) = %v, want %v", got, want)
+	}
+	if got, want := ix.Index(false), 1; got != want {
+		t.Errorf("HashIndex.Index(false) = %d, want %d", got, want)
+	}
+	if got, want := sorted.Le(2).ToSlice(), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedIndex.Le(2) = %v, want %v", got, want)
+	}
+
+	// Positions beyond the end of the Query are rebuilt away too.
+	*q = (*q)[:2]
+	if got, want := ix.Count(false), 1; got != want {
+		t.Errorf("HashIndex.Count(false) = %d, want %d", got, want)
+	}
+	if got, want := sorted.Ge(0).ToSlice(), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedIndex.Ge(0) = %v, want %v", got, want)
+	}
+}
+
+func TestIndex_Close(t *testing.T) {
+	q := members.Clone()
+	n := indexed.Load()
+	a := NewHashIndex(q, memberActive)
+	b := NewSortedIndex(q, memberAgeOf)
+	if got := indexed.Load(); got != n+1 {
+		t.Errorf("indexed = %d with two indexes on a Query, want %d", got, n+1)
+	}
+	a.Close()
+	a.Close()
+	if _, ok := trackers.Load(q); !ok {
+		t.Fatalf("Query untracked with an open index")
+	}
+	q.Take(1)
+	if got := memberNames(b.Ge(0)); !reflect.DeepEqual(got, []string{"Bob"}) {
+		t.Errorf("SortedIndex.Ge(0) = %v, want [Bob]", got)
+	}
+	b.Close()
+	if _, ok := trackers.Load(q); ok {
+		t.Errorf("Query tracked after closing all indexes")
+	}
+	if got := indexed.Load(); got != n {
+		t.Errorf("indexed = %d after closing all indexes, want %d", got, n)
+	}
+}
+
+func TestSortedIndex(t *testing.T) {
+	q := members.Clone()
+	ix := NewSortedIndex(q, memberAgeOf)
+	defer ix.Close()
+	tests := []struct {
+		name string
+		got  *Query[member]
+		want []string
+	}{
+		{"between", ix.Between(26, 35), []string{"Jenny", "Bob", "Jane"}},
+		{"empty range", ix.Between(35, 26), []string{}},
+		{"eq", ix.Eq(42), []string{"John"}},
+		{"eq missing", ix.Eq(43), []string{}},
+		{"lt", ix.Lt(31), []string{"Michael", "Jenny"}},
+		{"le", ix.Le(31), []string{"Michael", "Jenny", "Bob"}},
+		{"gt", ix.Gt(31), []string{"Jane", "John"}},
+		{"ge", ix.Ge(31), []string{"Bob", "Jane", "John"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := memberNames(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortedIndex = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	q.Where(func(m member) bool {
+		return m.Age < 40
+	})
+	if got, want := memberNames(ix.Ge(31)), []string{"Bob", "Jane"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedIndex.Ge(31) after Where = %v, want %v", got, want)
+	}
+}
+
+func Test_Prefix(t *testing.T) {
+	q := NewQuery([]string{"banana", "apple", "band", "ban", "bandana", "cherry", "b"})
+	ix := NewSortedIndex(q, func(s string) string { return s })
+	defer ix.Close()
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"ban", []string{"ban", "banana", "band", "bandana"}},
+		{"band", []string{"band", "bandana"}},
+		{"c", []string{"cherry"}},
+		{"", []string{"apple", "b", "ban", "banana", "band", "bandana", "cherry"}},
+		{"d", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Prefix(ix, tt.prefix).ToSlice(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prefix(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestSortedIndex_Concurrent(t *testing.T) {
+	q := members.Clone()
+	ix := NewSortedIndex(q, memberAgeOf)
+	defer ix.Close()
+	q.Reverse()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n := len(*ix.Between(18, 40)); n != 3 {
+				t.Errorf("SortedIndex.Between() = %d elements, want 3", n)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"iter"
+)
+
+// FromSeq creates a new Query with the elements yielded by seq, in the
+// order they are yielded.
+//
+// A nil seq yields an empty Query.
+func FromSeq[E any](seq iter.Seq[E]) *Query[E] {
+	q := Query[E](make([]E, 0))
+	if seq == nil {
+		return &q
+	}
+	for e := range seq {
+		q = append(q, e)
+	}
+	return &q
+}
+
+// FromSeq2 creates a new Query with every element yielded by seq placed
+// at the index yielded with it, such as the iterators returned by
+// Seq2, Backward and slices.All.
+//
+// The Query is as long as needed to hold the largest index, so the
+// indexes should be dense: a single large index allocates a Query of
+// that length. Indexes that are not yielded hold the zero value of E,
+// and an index yielded more than once holds the last element yielded
+// with it. Sparse or untrusted indexes are better collected into a map
+// with maps.Collect.
+//
+// FromSeq2 panics with an error wrapping ErrOutOfRange if an index is
+// negative. A nil seq yields an empty Query.
+func FromSeq2[E any](seq iter.Seq2[int, E]) *Query[E] {
+	q := Query[E](make([]E, 0))
+	if seq == nil {
+		return &q
+	}
+	for i, e := range seq {
+		if i < 0 {
+			panic(fmt.Errorf("sliceql.FromSeq2: %w", ErrOutOfRange))
+		}
+		if i >= len(q) {
+			q = append(q, make([]E, i+1-len(q))...)
+		}
+		q[i] = e
+	}
+	return &q
+}
+
+// Seq returns an iterator over the elements of the Query, from the
+// first to the last, for use in range loops and with functions such as
+// slices.Collect.
+//
+// The iterator yields the elements the Query holds when the loop over
+// it starts.
+func (q *Query[E]) Seq() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, e := range *q {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2 returns an iterator over the indexes and elements of the Query,
+// from the first to the last, like slices.All.
+//
+// The iterator yields the elements the Query holds when the loop over
+// it starts.
+func (q *Query[E]) Seq2() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		for i, e := range *q {
+			if !yield(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the indexes and elements of the
+// Query, from the last to the first, like slices.Backward.
+//
+// The iterator yields the elements the Query holds when the loop over
+// it starts.
+func (q *Query[E]) Backward() iter.Seq2[int, E] {
+	return func(yield func(int, E) bool) {
+		v := *q
+		for i := len(v) - 1; i >= 0; i-- {
+			if !yield(i, v[i]) {
+				return
+			}
+		}
+	}
+}
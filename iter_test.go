@@ -0,0 +1,216 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestQuery_Seq(t *testing.T) {
+	q := &Query[int]{1, 2, 3, 4}
+	if got := slices.Collect(q.Seq()); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Errorf("Query.Seq() = %v, want [1 2 3 4]", got)
+	}
+	var got []int
+	for e := range q.Seq() {
+		if e == 3 {
+			break
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Query.Seq() with break = %v, want [1 2]", got)
+	}
+	if got := slices.Collect((&Query[int]{}).Seq()); len(got) != 0 {
+		t.Errorf("Query.Seq() on empty Query = %v, want []", got)
+	}
+
+	// The iterator sees changes made to the Query after it was created.
+	s := q.Seq()
+	q.Where(isEven)
+	if got := slices.Collect(s); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Query.Seq() after Where() = %v, want [2 4]", got)
+	}
+}
+
+func TestQuery_Seq2(t *testing.T) {
+	q := &Query[string]{"a", "b", "c"}
+	want := map[int]string{0: "a", 1: "b", 2: "c"}
+	if got := maps.Collect(q.Seq2()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Query.Seq2() = %v, want %v", got, want)
+	}
+	tests := []struct {
+		name  string
+		seq   iter.Seq2[int, string]
+		stop  int
+		wantI []int
+	}{
+		{
+			name:  "forward",
+			seq:   q.Seq2(),
+			stop:  -1,
+			wantI: []int{0, 1, 2},
+		},
+		{
+			name:  "forward with break",
+			seq:   q.Seq2(),
+			stop:  1,
+			wantI: []int{0, 1},
+		},
+		{
+			name:  "backward",
+			seq:   q.Backward(),
+			stop:  -1,
+			wantI: []int{2, 1, 0},
+		},
+		{
+			name:  "backward with break",
+			seq:   q.Backward(),
+			stop:  2,
+			wantI: []int{2},
+		},
+		{
+			name:  "backward over empty Query",
+			seq:   (&Query[string]{}).Backward(),
+			stop:  -1,
+			wantI: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for i, e := range tt.seq {
+				if e != (*q)[i] {
+					t.Errorf("element %d = %q, want %q", i, e, (*q)[i])
+				}
+				got = append(got, i)
+				if i == tt.stop {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantI) {
+				t.Errorf("indexes = %v, want %v", got, tt.wantI)
+			}
+		})
+	}
+}
+
+func Test_FromSeq(t *testing.T) {
+	if got := FromSeq(slices.Values([]int{3, 1, 2})); !reflect.DeepEqual(got.ToSlice(), []int{3, 1, 2}) {
+		t.Errorf("FromSeq() = %v, want [3 1 2]", got)
+	}
+	if got := FromSeq[int](nil); got == nil || *got == nil || len(*got) != 0 {
+		t.Errorf("FromSeq(nil) = %#v, want an empty Query", got)
+	}
+	if got := FromSeq(NewLazy([]int{1, 2, 3, 4}).Where(isEven).Seq()); !reflect.DeepEqual(got.ToSlice(), []int{2, 4}) {
+		t.Errorf("FromSeq(Lazy.Seq()) = %v, want [2 4]", got)
+	}
+}
+
+func Test_FromSeq2(t *testing.T) {
+	q := &Query[int]{1, 2, 3}
+	tests := []struct {
+		name string
+		seq  iter.Seq2[int, int]
+		want []int
+	}{
+		{
+			name: "nil",
+			want: []int{},
+		},
+		{
+			name: "forward",
+			seq:  q.Seq2(),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "backward",
+			seq:  q.Backward(),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "out of order",
+			seq: func(yield func(int, int) bool) {
+				_ = yield(2, 7) && yield(0, 5) && yield(1, 6)
+			},
+			want: []int{5, 6, 7},
+		},
+		{
+			name: "duplicates",
+			seq: func(yield func(int, int) bool) {
+				_ = yield(0, 5) && yield(1, 6) && yield(0, 7)
+			},
+			want: []int{7, 6},
+		},
+		{
+			name: "gaps",
+			seq: func(yield func(int, int) bool) {
+				_ = yield(3, 7) && yield(1, 5)
+			},
+			want: []int{0, 5, 0, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromSeq2(tt.seq); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("FromSeq2() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FromSeq2_Negative(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  iter.Seq2[int, int]
+	}{
+		{
+			name: "first",
+			seq: func(yield func(int, int) bool) {
+				yield(-1, 0)
+			},
+		},
+		{
+			name: "after others",
+			seq: func(yield func(int, int) bool) {
+				_ = yield(0, 5) && yield(1, 6) && yield(-2, 7)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, ErrOutOfRange) {
+					t.Errorf("FromSeq2() recovered %v, want %v", err, ErrOutOfRange)
+				}
+			}()
+			FromSeq2(tt.seq)
+			t.Errorf("FromSeq2() did not panic")
+		})
+	}
+}
+
+func TestLazy_Seq(t *testing.T) {
+	pulled := 0
+	l := NewLazy([]int{1, 2, 3, 4, 5, 6}).Each(func(e int) int {
+		pulled++
+		return e
+	}).Where(isEven)
+	var got []int
+	for e := range l.Seq() {
+		got = append(got, e)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if !reflect.DeepEqual(got, []int{2, 4}) || pulled != 4 {
+		t.Errorf("Lazy.Seq() = %v after pulling %d elements, want [2 4] after 4", got, pulled)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+// lookup indexes the elements of q by the key returned from key,
+// keeping the source order of the elements that share a key.
+func lookup[E any, K comparable](q *Query[E], key func(E) K) map[K][]E {
+	m := make(map[K][]E)
+	for _, e := range *q {
+		k := key(e)
+		m[k] = append(m[k], e)
+	}
+	return m
+}
+
+// Join correlates the elements of two queries based on matching keys
+// and returns an inner join.
+//
+// The keys of the outer and inner elements are computed with outerKey
+// and innerKey, and result is called for every pair with equal keys.
+// The inner Query is hashed once, so the join runs in O(n+m) time plus
+// the size of the result.
+//
+// The result keeps the order of the outer Query, and for every outer
+// element the order of its matching inner elements. A nil function
+// yields an empty Query.
+func Join[O, I any, K comparable, R any](outer *Query[O], inner *Query[I], outerKey func(O) K, innerKey func(I) K, result func(O, I) R) *Query[R] {
+	r := Query[R](make([]R, 0))
+	if outerKey == nil || innerKey == nil || result == nil {
+		return &r
+	}
+	m := lookup(inner, innerKey)
+	for _, o := range *outer {
+		for _, i := range m[outerKey(o)] {
+			r = append(r, result(o, i))
+		}
+	}
+	return &r
+}
+
+// LeftJoin correlates the elements of two queries based on matching keys
+// and returns a left outer join.
+//
+// It behaves like Join, except that result is also called once for every
+// outer element without a matching inner element. The inner argument of
+// result points to the matching inner element, or is nil if there is
+// none. A nil function yields an empty Query.
+func LeftJoin[O, I any, K comparable, R any](outer *Query[O], inner *Query[I], outerKey func(O) K, innerKey func(I) K, result func(O, *I) R) *Query[R] {
+	r := Query[R](make([]R, 0))
+	if outerKey == nil || innerKey == nil || result == nil {
+		return &r
+	}
+	m := lookup(inner, innerKey)
+	for _, o := range *outer {
+		matches := m[outerKey(o)]
+		if len(matches) == 0 {
+			r = append(r, result(o, nil))
+			continue
+		}
+		for _, i := range matches {
+			i := i
+			r = append(r, result(o, &i))
+		}
+	}
+	return &r
+}
+
+// FullOuterJoin correlates the elements of two queries based on matching
+// keys and returns a full outer join.
+//
+// The result starts with the rows of LeftJoin, followed by one row for
+// every inner element without a matching outer element, in the order of
+// the inner Query. The arguments of result point to the joined elements;
+// the side without a match is nil. A nil function yields an empty Query.
+func FullOuterJoin[O, I any, K comparable, R any](outer *Query[O], inner *Query[I], outerKey func(O) K, innerKey func(I) K, result func(*O, *I) R) *Query[R] {
+	r := Query[R](make([]R, 0))
+	if outerKey == nil || innerKey == nil || result == nil {
+		return &r
+	}
+	m := lookup(inner, innerKey)
+	matched := make(map[K]bool)
+	for _, o := range *outer {
+		o := o
+		k := outerKey(o)
+		matches := m[k]
+		if len(matches) == 0 {
+			r = append(r, result(&o, nil))
+			continue
+		}
+		matched[k] = true
+		for _, i := range matches {
+			i := i
+			r = append(r, result(&o, &i))
+		}
+	}
+	for _, i := range *inner {
+		i := i
+		if !matched[innerKey(i)] {
+			r = append(r, result(nil, &i))
+		}
+	}
+	return &r
+}
+
+// GroupJoin correlates the elements of two queries based on matching keys
+// and groups the inner matches of every outer element.
+//
+// The function result is called exactly once for every outer element,
+// with a Query of its matching inner elements, which is empty if there
+// are none. A nil function yields an empty Query.
+func GroupJoin[O, I any, K comparable, R any](outer *Query[O], inner *Query[I], outerKey func(O) K, innerKey func(I) K, result func(O, *Query[I]) R) *Query[R] {
+	r := Query[R](make([]R, 0))
+	if outerKey == nil || innerKey == nil || result == nil {
+		return &r
+	}
+	m := lookup(inner, innerKey)
+	for _, o := range *outer {
+		matches := Query[I](append(make([]I, 0), m[outerKey(o)]...))
+		r = append(r, result(o, &matches))
+	}
+	return &r
+}
+
+// CrossJoin returns the Cartesian product of two queries.
+//
+// The function result is called for every combination of an outer and
+// an inner element, iterating the inner Query for every outer element.
+// A nil result yields an empty Query.
+func CrossJoin[O, I, R any](outer *Query[O], inner *Query[I], result func(O, I) R) *Query[R] {
+	r := Query[R](make([]R, 0))
+	if result == nil {
+		return &r
+	}
+	for _, o := range *outer {
+		for _, i := range *inner {
+			r = append(r, result(o, i))
+		}
+	}
+	return &r
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type customer struct {
+	ID   int
+	Name string
+}
+
+type order struct {
+	ID         int
+	CustomerID int
+}
+
+var (
+	joinCustomers = &Query[customer]{{1, "Ann"}, {2, "Bob"}, {3, "Cid"}}
+	joinOrders    = &Query[order]{{10, 2}, {11, 1}, {12, 2}, {13, 4}}
+)
+
+func customerID(c customer) int { return c.ID }
+
+func orderCustomerID(o order) int { return o.CustomerID }
+
+func Test_Join(t *testing.T) {
+	tests := []struct {
+		name   string
+		result func(customer, order) string
+		want   *Query[string]
+	}{
+		{
+			name:   "nil result",
+			result: nil,
+			want:   &Query[string]{},
+		},
+		{
+			name: "inner join",
+			result: func(c customer, o order) string {
+				return fmt.Sprintf("%s:%d", c.Name, o.ID)
+			},
+			want: &Query[string]{"Ann:11", "Bob:10", "Bob:12"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Join(joinCustomers, joinOrders, customerID, orderCustomerID, tt.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Join() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LeftJoin(t *testing.T) {
+	got := LeftJoin(joinCustomers, joinOrders, customerID, orderCustomerID, func(c customer, o *order) string {
+		if o == nil {
+			return c.Name + ":-"
+		}
+		return fmt.Sprintf("%s:%d", c.Name, o.ID)
+	})
+	want := &Query[string]{"Ann:11", "Bob:10", "Bob:12", "Cid:-"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LeftJoin() = %v, want %v", got, want)
+	}
+}
+
+func Test_FullOuterJoin(t *testing.T) {
+	got := FullOuterJoin(joinCustomers, joinOrders, customerID, orderCustomerID, func(c *customer, o *order) string {
+		switch {
+		case c == nil:
+			return fmt.Sprintf("-:%d", o.ID)
+		case o == nil:
+			return c.Name + ":-"
+		}
+		return fmt.Sprintf("%s:%d", c.Name, o.ID)
+	})
+	want := &Query[string]{"Ann:11", "Bob:10", "Bob:12", "Cid:-", "-:13"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FullOuterJoin() = %v, want %v", got, want)
+	}
+}
+
+func Test_GroupJoin(t *testing.T) {
+	got := GroupJoin(joinCustomers, joinOrders, customerID, orderCustomerID, func(c customer, orders *Query[order]) string {
+		return fmt.Sprintf("%s:%d", c.Name, len(*orders))
+	})
+	want := &Query[string]{"Ann:1", "Bob:2", "Cid:0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupJoin() = %v, want %v", got, want)
+	}
+}
+
+func Test_CrossJoin(t *testing.T) {
+	type args struct {
+		outer  *Query[int]
+		inner  *Query[string]
+		result func(int, string) string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[string]
+	}{
+		{
+			name: "nil result",
+			args: args{
+				outer: &Query[int]{1, 2},
+				inner: &Query[string]{"a"},
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "empty inner",
+			args: args{
+				outer: &Query[int]{1, 2},
+				inner: &Query[string]{},
+				result: func(i int, s string) string {
+					return fmt.Sprint(i, s)
+				},
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "product",
+			args: args{
+				outer: &Query[int]{1, 2},
+				inner: &Query[string]{"a", "b"},
+				result: func(i int, s string) string {
+					return fmt.Sprint(i, s)
+				},
+			},
+			want: &Query[string]{"1a", "1b", "2a", "2b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CrossJoin(tt.args.outer, tt.args.inner, tt.args.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CrossJoin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
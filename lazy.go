@@ -0,0 +1,356 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// A Lazy is a deferred query over a slice.
+//
+// Unlike the methods of Query, which run immediately, the methods of Lazy
+// only record a stage of the pipeline. Elements are pulled through the
+// stages when a terminal operation such as First, Count, Fold or ToSlice
+// runs, and stages like Take stop pulling as soon as they are satisfied.
+//
+// A Lazy never modifies its source slice, and every stage method returns
+// a new Lazy, so a pipeline can be shared and extended independently.
+//
+// Before a pipeline runs, its stages are rewritten into equivalent ones
+// that do less work: consecutive Where stages are fused, a Sort followed
+// by Take only keeps the elements it yields in a heap rather than sorting
+// every element, pairs of Reverse cancel out, and Count without a test
+// is computed from the length of the source where possible. The
+// pipeline yields the same elements as the same chain of Query methods,
+// but may call the functions passed to its stages fewer times, so they
+// should not have side effects. Plan and Explain show the rewritten
+// stages.
+type Lazy[E any] struct {
+	src    []E
+	stages []stage[E]
+	// prof is non-nil if the pipeline is profiled.
+	prof *profile
+}
+
+// stageKind identifies the operation recorded by a stage.
+type stageKind int
+
+const (
+	whereStage stageKind = iota
+	eachStage
+	skipStage
+	takeStage
+	sortStage
+	reverseStage
+	// topKStage is a Sort whose output is limited to its first n
+	// elements, see optimize.
+	topKStage
+)
+
+// stage is a single recorded step of a Lazy pipeline.
+type stage[E any] struct {
+	kind stageKind
+	test func(E) bool
+	fn   func(E) E
+	less func(E, E) bool
+	n    int
+}
+
+// NewLazy creates a new Lazy pipeline over the slice v.
+//
+// The slice is not copied, but it is never modified by the pipeline.
+func NewLazy[E any](v []E) *Lazy[E] {
+	return &Lazy[E]{src: v}
+}
+
+// Lazy returns a deferred pipeline over the elements of the Query.
+//
+// Stages added to the returned Lazy do not modify the Query.
+func (q *Query[E]) Lazy() *Lazy[E] {
+	return NewLazy([]E(*q))
+}
+
+// with returns a copy of the pipeline with s appended to its stages.
+func (l *Lazy[E]) with(s stage[E]) *Lazy[E] {
+	r := &Lazy[E]{
+		src:    l.src,
+		stages: append(slices.Clip(l.stages), s),
+	}
+	if l.prof != nil {
+		r.prof = &profile{}
+	}
+	return r
+}
+
+// Where adds a stage that keeps only the elements satisfying f.
+//
+// As with Query.Where, a nil f filters out every element.
+func (l *Lazy[E]) Where(f func(E) bool) *Lazy[E] {
+	return l.with(stage[E]{kind: whereStage, test: f})
+}
+
+// Each adds a stage that replaces every element with the result of f.
+//
+// A nil f leaves the pipeline unchanged.
+func (l *Lazy[E]) Each(f func(E) E) *Lazy[E] {
+	if f == nil {
+		return l
+	}
+	return l.with(stage[E]{kind: eachStage, fn: f})
+}
+
+// Skip adds a stage that drops the first n elements.
+//
+// As with Query.Skip, a negative n skips nothing and an n beyond the
+// number of elements yields an empty result.
+func (l *Lazy[E]) Skip(n int) *Lazy[E] {
+	return l.with(stage[E]{kind: skipStage, n: max(n, 0)})
+}
+
+// Take adds a stage that yields at most the first n elements.
+//
+// Once n elements have been yielded no further elements are pulled
+// from the preceding stages. A negative n yields nothing.
+func (l *Lazy[E]) Take(n int) *Lazy[E] {
+	return l.with(stage[E]{kind: takeStage, n: max(n, 0)})
+}
+
+// Sort adds a stage that orders the elements using the less function.
+//
+// Sorting needs every element of the preceding stages, so this stage
+// buffers its input. The sort is stable. A nil less leaves the pipeline
+// unchanged.
+func (l *Lazy[E]) Sort(le func(E, E) bool) *Lazy[E] {
+	if le == nil {
+		return l
+	}
+	return l.with(stage[E]{kind: sortStage, less: le})
+}
+
+// Reverse adds a stage that reverses the order of the elements.
+//
+// Like Sort, this stage buffers its input.
+func (l *Lazy[E]) Reverse() *Lazy[E] {
+	return l.with(stage[E]{kind: reverseStage})
+}
+
+// All reports whether every element satisfies f.
+//
+// To match Query.All, it returns false for an empty result or a nil f.
+func (l *Lazy[E]) All(f func(E) bool) bool {
+	if f == nil {
+		return false
+	}
+	n, ok := 0, true
+	l.run(func(e E) bool {
+		n++
+		ok = f(e)
+		return ok
+	})
+	return n > 0 && ok
+}
+
+// Any reports whether any element satisfies f.
+//
+// It stops pulling elements at the first match.
+func (l *Lazy[E]) Any(f func(E) bool) bool {
+	if f == nil {
+		return false
+	}
+	found := false
+	l.run(func(E) bool {
+		found = true
+		return false
+	}, stage[E]{kind: whereStage, test: f})
+	return found
+}
+
+// Count returns the number of elements that satisfy f.
+//
+// A nil f counts every element.
+func (l *Lazy[E]) Count(f func(E) bool) int {
+	var extra []stage[E]
+	if f != nil {
+		extra = append(extra, stage[E]{kind: whereStage, test: f})
+	}
+	if l.prof == nil {
+		if n, ok := count(len(l.src), optimize(append(slices.Clip(l.stages), extra...))); ok {
+			return n
+		}
+	}
+	n := 0
+	l.run(func(E) bool {
+		n++
+		return true
+	}, extra...)
+	return n
+}
+
+// First returns the first element of the pipeline.
+//
+// Only a single element is pulled. It panics if the result is empty.
+func (l *Lazy[E]) First() E {
+	e, err := l.first("First")
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// TryFirst returns the first element of the pipeline.
+//
+// Only a single element is pulled. The error wraps ErrEmpty if the
+// result is empty.
+func (l *Lazy[E]) TryFirst() (E, error) {
+	return l.first("TryFirst")
+}
+
+// first pulls the first element or returns an error naming op.
+func (l *Lazy[E]) first(op string) (E, error) {
+	var first E
+	found := false
+	l.run(func(e E) bool {
+		first, found = e, true
+		return false
+	})
+	if !found {
+		return first, fmt.Errorf("sliceql.%s: %w", op, ErrEmpty)
+	}
+	return first, nil
+}
+
+// Fold runs the pipeline and combines its elements with f,
+// starting from the initial value v.
+func (l *Lazy[E]) Fold(v E, f func(E, E) E) E {
+	result := v
+	l.run(func(e E) bool {
+		result = f(result, e)
+		return true
+	})
+	return result
+}
+
+// Seq returns an iterator that runs the pipeline and yields its
+// elements. The pipeline runs anew every time the iterator is used, and
+// stops pulling elements when the loop over the iterator breaks.
+func (l *Lazy[E]) Seq() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		l.run(yield)
+	}
+}
+
+// ToQuery runs the pipeline and returns its elements as a new Query.
+func (l *Lazy[E]) ToQuery() *Query[E] {
+	return NewQuery(l.ToSlice())
+}
+
+// ToSlice runs the pipeline and returns its elements as a new slice.
+func (l *Lazy[E]) ToSlice() []E {
+	return collect(l.Seq())
+}
+
+// run optimizes the pipeline, with the extra stages of a terminal
+// operation appended, and runs it, passing its elements to yield until
+// it returns false. A profiled pipeline records the statistics of the
+// run.
+func (l *Lazy[E]) run(yield func(E) bool, extra ...stage[E]) {
+	stages := optimize(append(slices.Clip(l.stages), extra...))
+	if l.prof != nil {
+		l.profiled(stages, yield)
+		return
+	}
+	compose(l.src, stages)(yield)
+}
+
+// compose composes the stages over the slice src into a single iterator.
+func compose[E any](src []E, stages []stage[E]) iter.Seq[E] {
+	s := slices.Values(src)
+	for _, st := range stages {
+		s = st.apply(s)
+	}
+	return s
+}
+
+// apply wraps the iterator s with the operation of the stage.
+func (st stage[E]) apply(s iter.Seq[E]) iter.Seq[E] {
+	switch st.kind {
+	case whereStage:
+		return func(yield func(E) bool) {
+			if st.test == nil {
+				return
+			}
+			s(func(e E) bool {
+				return !st.test(e) || yield(e)
+			})
+		}
+	case eachStage:
+		return func(yield func(E) bool) {
+			s(func(e E) bool {
+				return yield(st.fn(e))
+			})
+		}
+	case skipStage:
+		return func(yield func(E) bool) {
+			i := 0
+			s(func(e E) bool {
+				if i < st.n {
+					i++
+					return true
+				}
+				return yield(e)
+			})
+		}
+	case takeStage:
+		return func(yield func(E) bool) {
+			if st.n < 1 {
+				return
+			}
+			i := 0
+			s(func(e E) bool {
+				i++
+				return yield(e) && i < st.n
+			})
+		}
+	case sortStage:
+		return func(yield func(E) bool) {
+			v := collect(s)
+			sort.SliceStable(v, func(i, j int) bool {
+				return st.less(v[i], v[j])
+			})
+			slices.Values(v)(yield)
+		}
+	case topKStage:
+		return func(yield func(E) bool) {
+			if st.n < 1 {
+				return
+			}
+			slices.Values(selectTopK(s, st.n, st.less))(yield)
+		}
+	case reverseStage:
+		return func(yield func(E) bool) {
+			v := collect(s)
+			for i := len(v) - 1; i >= 0; i-- {
+				if !yield(v[i]) {
+					return
+				}
+			}
+		}
+	}
+	panic("sliceql: unknown stage")
+}
+
+// collect gathers the elements of s into a new slice. Unlike
+// slices.Collect, it returns an empty rather than a nil slice if s
+// yields no elements.
+func collect[E any](s iter.Seq[E]) []E {
+	v := make([]E, 0)
+	for e := range s {
+		v = append(v, e)
+	}
+	return v
+}
@@ -0,0 +1,301 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func isEven(e int) bool {
+	return e%2 == 0
+}
+
+func Test_NewLazy(t *testing.T) {
+	type args struct {
+		v []int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []int
+	}{
+		{
+			name: "empty slice",
+			args: args{
+				v: []int{},
+			},
+			want: []int{},
+		},
+		{
+			name: "nil slice",
+			args: args{
+				v: nil,
+			},
+			want: []int{},
+		},
+		{
+			name: "non-empty slice",
+			args: args{
+				v: []int{1, 2, 3},
+			},
+			want: []int{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLazy(tt.args.v).ToSlice(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewLazy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazy_ToSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		l    *Lazy[int]
+		want []int
+	}{
+		{
+			name: "where",
+			l:    NewQuery([]int{1, 2, 3, 4, 5}).Lazy().Where(isEven),
+			want: []int{2, 4},
+		},
+		{
+			name: "nil where",
+			l:    NewQuery([]int{1, 2, 3, 4, 5}).Lazy().Where(nil),
+			want: []int{},
+		},
+		{
+			name: "each",
+			l: NewQuery([]int{1, 2, 3}).Lazy().Each(func(e int) int {
+				return e * 10
+			}),
+			want: []int{10, 20, 30},
+		},
+		{
+			name: "skip and take",
+			l:    NewQuery([]int{1, 2, 3, 4, 5}).Lazy().Skip(1).Take(3),
+			want: []int{2, 3, 4},
+		},
+		{
+			name: "skip overflow",
+			l:    NewQuery([]int{1, 2, 3}).Lazy().Skip(10),
+			want: []int{},
+		},
+		{
+			name: "negative skip",
+			l:    NewQuery([]int{1, 2, 3}).Lazy().Skip(-1),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "take overflow",
+			l:    NewQuery([]int{1, 2, 3}).Lazy().Take(10),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "take zero",
+			l:    NewQuery([]int{1, 2, 3}).Lazy().Take(0),
+			want: []int{},
+		},
+		{
+			name: "sort",
+			l: NewQuery([]int{3, 1, 2}).Lazy().Sort(func(e1, e2 int) bool {
+				return e1 < e2
+			}),
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "reverse",
+			l:    NewQuery([]int{1, 2, 3}).Lazy().Reverse(),
+			want: []int{3, 2, 1},
+		},
+		{
+			name: "chain",
+			l: NewQuery([]int{5, 4, 3, 2, 1, 6}).Lazy().Where(isEven).Sort(func(e1, e2 int) bool {
+				return e1 < e2
+			}).Reverse().Take(2),
+			want: []int{6, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.ToSlice(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lazy.ToSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazy_Deferred(t *testing.T) {
+	calls := 0
+	l := NewQuery([]int{1, 2, 3, 4, 5, 6, 7, 8}).Lazy().Where(func(e int) bool {
+		calls++
+		return isEven(e)
+	})
+	if calls != 0 {
+		t.Fatalf("Lazy.Where() called predicate %d times before execution", calls)
+	}
+	if got := l.Take(2).ToSlice(); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("Lazy.Take() = %v, want %v", got, []int{2, 4})
+	}
+	if calls != 4 {
+		t.Errorf("Lazy.Take() pulled %d elements, want 4", calls)
+	}
+	calls = 0
+	if got := l.First(); got != 2 {
+		t.Errorf("Lazy.First() = %v, want 2", got)
+	}
+	if calls != 2 {
+		t.Errorf("Lazy.First() pulled %d elements, want 2", calls)
+	}
+}
+
+func TestLazy_Immutable(t *testing.T) {
+	v := []int{3, 1, 2}
+	base := NewLazy(v)
+	_ = base.Sort(func(e1, e2 int) bool {
+		return e1 < e2
+	}).Reverse().Each(func(e int) int {
+		return -e
+	}).ToSlice()
+	if !reflect.DeepEqual(v, []int{3, 1, 2}) {
+		t.Errorf("source modified to %v", v)
+	}
+	if got := base.ToSlice(); !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("Lazy.ToSlice() = %v, want %v", got, []int{3, 1, 2})
+	}
+}
+
+func TestLazy_Any(t *testing.T) {
+	tests := []struct {
+		name string
+		l    *Lazy[int]
+		f    func(int) bool
+		want bool
+	}{
+		{
+			name: "nil predicate",
+			l:    NewLazy([]int{1, 2, 3}),
+			f:    nil,
+			want: false,
+		},
+		{
+			name: "match",
+			l:    NewLazy([]int{1, 2, 3}),
+			f:    isEven,
+			want: true,
+		},
+		{
+			name: "no match",
+			l:    NewLazy([]int{1, 2, 3}).Where(func(e int) bool { return e != 2 }),
+			f:    isEven,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Any(tt.f); got != tt.want {
+				t.Errorf("Lazy.Any() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazy_All(t *testing.T) {
+	tests := []struct {
+		name string
+		l    *Lazy[int]
+		f    func(int) bool
+		want bool
+	}{
+		{
+			name: "nil predicate",
+			l:    NewLazy([]int{2, 4}),
+			f:    nil,
+			want: false,
+		},
+		{
+			name: "empty",
+			l:    NewLazy([]int{}),
+			f:    isEven,
+			want: false,
+		},
+		{
+			name: "all match",
+			l:    NewLazy([]int{1, 2, 3, 4}).Where(isEven),
+			f:    isEven,
+			want: true,
+		},
+		{
+			name: "some match",
+			l:    NewLazy([]int{1, 2, 3, 4}),
+			f:    isEven,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.All(tt.f); got != tt.want {
+				t.Errorf("Lazy.All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazy_Count(t *testing.T) {
+	tests := []struct {
+		name string
+		l    *Lazy[int]
+		f    func(int) bool
+		want int
+	}{
+		{
+			name: "nil predicate",
+			l:    NewLazy([]int{1, 2, 3}).Skip(1),
+			f:    nil,
+			want: 2,
+		},
+		{
+			name: "predicate",
+			l:    NewLazy([]int{1, 2, 3, 4}),
+			f:    isEven,
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Count(tt.f); got != tt.want {
+				t.Errorf("Lazy.Count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLazy_First(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Lazy.First() did not panic on empty result")
+		}
+	}()
+	NewLazy([]int{1, 3}).Where(isEven).First()
+}
+
+func TestLazy_Fold(t *testing.T) {
+	got := NewQuery([]int{1, 2, 3, 4}).Lazy().Where(isEven).Fold(0, func(acc, e int) int {
+		return acc + e
+	})
+	if got != 6 {
+		t.Errorf("Lazy.Fold() = %v, want 6", got)
+	}
+}
+
+func TestLazy_ToQuery(t *testing.T) {
+	got := NewQuery([]int{1, 2, 3, 4}).Lazy().Where(isEven).ToQuery()
+	if want := (&Query[int]{2, 4}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lazy.ToQuery() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A ParseError describes a problem with the text of a query.
+type ParseError struct {
+	// Column is the 1-based byte offset of the offending
+	// token in the query text.
+	Column int
+	// Msg describes the problem.
+	Msg string
+}
+
+// Error returns a string representation of the ParseError.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("sliceql: column %d: %s", e.Column, e.Msg)
+}
+
+// errorAt returns a ParseError for the 0-based offset pos.
+func errorAt(pos int, format string, args ...any) error {
+	return &ParseError{Column: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenKind identifies the lexical class of a token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokKeyword
+	tokNumber
+	tokString
+	tokOp
+	tokComma
+	tokLParen
+	tokRParen
+	tokStar
+)
+
+// keywords are the reserved words of the query language.
+// They are matched case-insensitively.
+var keywords = map[string]bool{
+	"AND":     true,
+	"AS":      true,
+	"ASC":     true,
+	"BETWEEN": true,
+	"BY":      true,
+	"DESC":    true,
+	"FALSE":   true,
+	"GROUP":   true,
+	"HAVING":  true,
+	"IN":      true,
+	"LIKE":    true,
+	"LIMIT":   true,
+	"NOT":     true,
+	"NULL":    true,
+	"OFFSET":  true,
+	"OR":      true,
+	"ORDER":   true,
+	"SELECT":  true,
+	"TRUE":    true,
+	"WHERE":   true,
+}
+
+// token is a lexical token of a query.
+type token struct {
+	kind tokenKind
+	pos  int
+	// text is the source text of the token. Keywords are upper-cased,
+	// and string literals are unquoted.
+	text string
+}
+
+// String returns a description of the token for error messages.
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string %s", quote(t.text))
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits the query text into tokens, ending with a tokEOF token.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r, w := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += w
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(src) {
+				r, w := utf8.DecodeRuneInString(src[j:])
+				if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += w
+			}
+			text := src[i:j]
+			if strings.HasSuffix(text, ".") || strings.Contains(text, "..") {
+				return nil, errorAt(i, "malformed field path %q", text)
+			}
+			if upper := strings.ToUpper(text); keywords[upper] {
+				tokens = append(tokens, token{kind: tokKeyword, pos: i, text: upper})
+			} else {
+				tokens = append(tokens, token{kind: tokIdent, pos: i, text: text})
+			}
+			i = j
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				j++
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				for j < len(src) && isDigit(src[j]) {
+					j++
+				}
+			}
+			tokens = append(tokens, token{kind: tokNumber, pos: i, text: src[i:j]})
+			i = j
+		case r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(src) {
+					return nil, errorAt(i, "unterminated string")
+				}
+				if src[j] == '\'' {
+					// A doubled quote stands for a single quote.
+					if j+1 < len(src) && src[j+1] == '\'' {
+						b.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(src[j])
+				j++
+			}
+			tokens = append(tokens, token{kind: tokString, pos: i, text: b.String()})
+			i = j + 1
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, pos: i, text: ","})
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, pos: i, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, pos: i, text: ")"})
+			i++
+		case r == '*':
+			tokens = append(tokens, token{kind: tokStar, pos: i, text: "*"})
+			i++
+		case strings.ContainsRune("=!<>-", r):
+			op := string(r)
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "!=", "<>", "<=", ">=":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, errorAt(i, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokOp, pos: i, text: op})
+			i += len(op)
+		default:
+			return nil, errorAt(i, "unexpected character %q", r)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// quote returns s as a single-quoted string literal of the query language.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"sort"
+)
+
+// optimize rewrites the stages of a pipeline into equivalent stages
+// that do less work. The rewritten pipeline yields the same elements
+// in the same order, but may call the functions of its stages fewer
+// times:
+//
+//   - adjacent Where stages are fused into a single one,
+//   - adjacent Reverse stages cancel each other,
+//   - adjacent Skip stages and adjacent Take stages are merged, and a
+//     Skip is moved before a Take so that it can merge with other Skips,
+//   - a Sort followed by Skip and Take stages only keeps the elements
+//     they need in a heap, see topKStage.
+//
+// The stages passed in are not modified.
+func optimize[E any](stages []stage[E]) []stage[E] {
+	out := make([]stage[E], len(stages))
+	copy(out, stages)
+	for rewritten := true; rewritten; {
+		rewritten = false
+		for i := 0; i+1 < len(out); i++ {
+			if r, ok := combine(out[i], out[i+1]); ok {
+				out = append(out[:i], append(r, out[i+2:]...)...)
+				rewritten = true
+				break
+			}
+		}
+	}
+	for i, st := range out {
+		if st.kind != sortStage {
+			continue
+		}
+		if n, ok := limit(out[i+1:]); ok {
+			out[i] = stage[E]{kind: topKStage, less: st.less, n: n}
+		}
+	}
+	return out
+}
+
+// combine rewrites the adjacent stages a and b into equivalent stages.
+// It returns ok false if no rule applies.
+func combine[E any](a, b stage[E]) (r []stage[E], ok bool) {
+	switch {
+	case a.kind == whereStage && b.kind == whereStage:
+		return []stage[E]{{kind: whereStage, test: and(a.test, b.test)}}, true
+	case a.kind == reverseStage && b.kind == reverseStage:
+		return nil, true
+	case a.kind == skipStage && b.kind == skipStage:
+		return []stage[E]{{kind: skipStage, n: addSat(a.n, b.n)}}, true
+	case a.kind == takeStage && b.kind == takeStage:
+		return []stage[E]{{kind: takeStage, n: min(a.n, b.n)}}, true
+	case a.kind == takeStage && b.kind == skipStage:
+		return []stage[E]{
+			{kind: skipStage, n: b.n},
+			{kind: takeStage, n: max(a.n-b.n, 0)},
+		}, true
+	}
+	return nil, false
+}
+
+// limit returns the number of leading elements of their input that
+// the Skip and Take stages at the start of stages can yield. It returns
+// ok false if they can yield every element.
+func limit[E any](stages []stage[E]) (n int, ok bool) {
+	skip := 0
+	for _, st := range stages {
+		switch st.kind {
+		case skipStage:
+			skip = addSat(skip, st.n)
+		case takeStage:
+			return addSat(skip, st.n), true
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// count returns the number of elements the stages yield for an input
+// of n elements without running them. It returns ok false if the count
+// depends on the elements.
+func count[E any](n int, stages []stage[E]) (int, bool) {
+	for _, st := range stages {
+		switch st.kind {
+		case eachStage, sortStage, reverseStage:
+		case skipStage:
+			n = max(n-st.n, 0)
+		case takeStage, topKStage:
+			n = min(n, st.n)
+		default:
+			return 0, false
+		}
+	}
+	return n, true
+}
+
+// and returns the conjunction of the tests f and g. As with Where,
+// a nil test is false for every element.
+func and[E any](f, g func(E) bool) func(E) bool {
+	if f == nil || g == nil {
+		return nil
+	}
+	return func(e E) bool {
+		return f(e) && g(e)
+	}
+}
+
+// addSat returns a + b for non-negative a and b, saturating at the
+// largest int.
+func addSat(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// topK is a max-heap of the k least elements seen so far. Elements
+// that compare equal are ordered by their position in the input, so
+// the heap yields the first k elements of a stable sort.
+type topK[E any] struct {
+	less  func(E, E) bool
+	items []ranked[E]
+}
+
+// ranked is an element with its position in the input.
+type ranked[E any] struct {
+	e E
+	i int
+}
+
+// before reports whether a comes before b in the stable order.
+func (h *topK[E]) before(a, b ranked[E]) bool {
+	if h.less(a.e, b.e) {
+		return true
+	}
+	return !h.less(b.e, a.e) && a.i < b.i
+}
+
+func (h *topK[E]) Len() int           { return len(h.items) }
+func (h *topK[E]) Less(i, j int) bool { return h.before(h.items[j], h.items[i]) }
+func (h *topK[E]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *topK[E]) Push(x any)         { h.items = append(h.items, x.(ranked[E])) }
+func (h *topK[E]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// selectTopK returns the first k elements of s in stable sort order
+// using the less function, keeping at most k elements in memory.
+func selectTopK[E any](s iter.Seq[E], k int, less func(E, E) bool) []E {
+	h := &topK[E]{less: less, items: make([]ranked[E], 0, min(k, 1024))}
+	i := 0
+	s(func(e E) bool {
+		r := ranked[E]{e, i}
+		i++
+		switch {
+		case len(h.items) < k:
+			heap.Push(h, r)
+		case h.before(r, h.items[0]):
+			h.items[0] = r
+			heap.Fix(h, 0)
+		}
+		return true
+	})
+	sort.Slice(h.items, func(i, j int) bool {
+		return h.before(h.items[i], h.items[j])
+	})
+	v := make([]E, len(h.items))
+	for i, r := range h.items {
+		v[i] = r.e
+	}
+	return v
+}
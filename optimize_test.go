@@ -0,0 +1,188 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_optimize(t *testing.T) {
+	less := func(a, b int) bool {
+		return a < b
+	}
+	l := NewLazy([]int{})
+	tests := []struct {
+		name string
+		l    *Lazy[int]
+		want string
+	}{
+		{
+			name: "where fusion",
+			l:    l.Where(isEven).Where(isEven).Each(func(e int) int { return e }).Where(isEven),
+			want: "Slice(0) Where Each Where",
+		},
+		{
+			name: "reverse pairs",
+			l:    l.Reverse().Reverse().Reverse().Where(isEven).Reverse().Reverse(),
+			want: "Slice(0) Reverse Where",
+		},
+		{
+			name: "skip and take",
+			l:    l.Skip(2).Skip(3).Take(10).Take(4).Skip(1),
+			want: "Slice(0) Skip(6) Take(3)",
+		},
+		{
+			name: "top-k",
+			l:    l.Sort(less).Skip(2).Take(3),
+			want: "Slice(0) TopK(5) Skip(2) Take(3)",
+		},
+		{
+			name: "top-k after merge",
+			l:    l.Sort(less).Take(5).Skip(2),
+			want: "Slice(0) TopK(5) Skip(2) Take(3)",
+		},
+		{
+			name: "sort without limit",
+			l:    l.Sort(less).Skip(2).Reverse().Take(1),
+			want: "Slice(0) Sort Skip(2) Reverse Take(1)",
+		},
+		{
+			name: "saturating skip",
+			l:    l.Skip(math.MaxInt).Skip(1),
+			want: "Slice(0) Skip(9223372036854775807)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []string
+			for _, st := range tt.l.Plan().Stages {
+				op := st.Op
+				if st.Arg != "" {
+					op += "(" + st.Arg + ")"
+				}
+				ops = append(ops, op)
+			}
+			if got := strings.Join(ops, " "); got != tt.want {
+				t.Errorf("Lazy.Plan() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// op is a step of a random pipeline, applied both to a Lazy and,
+// eagerly, to a Query.
+type op struct {
+	name  string
+	lazy  func(*Lazy[int]) *Lazy[int]
+	eager func(*Query[int]) *Query[int]
+}
+
+func randomOp(r *rand.Rand) op {
+	n := r.Intn(15) - 2
+	switch r.Intn(8) {
+	case 0, 1:
+		m := r.Intn(3) + 2
+		f := func(e int) bool { return e%m != 0 }
+		return op{"Where", func(l *Lazy[int]) *Lazy[int] { return l.Where(f) }, func(q *Query[int]) *Query[int] { return q.Where(f) }}
+	case 2:
+		f := func(e int) int { return e*7%23 - 5 }
+		return op{"Each", func(l *Lazy[int]) *Lazy[int] { return l.Each(f) }, func(q *Query[int]) *Query[int] { return q.Each(f) }}
+	case 3:
+		return op{"Skip", func(l *Lazy[int]) *Lazy[int] { return l.Skip(n) }, func(q *Query[int]) *Query[int] { return q.Skip(n) }}
+	case 4, 5:
+		return op{"Take", func(l *Lazy[int]) *Lazy[int] { return l.Take(n) }, func(q *Query[int]) *Query[int] { return q.Take(n) }}
+	case 6:
+		less := func(a, b int) bool { return a < b }
+		if r.Intn(2) == 0 {
+			less = func(a, b int) bool { return a > b }
+		}
+		return op{"Sort", func(l *Lazy[int]) *Lazy[int] { return l.Sort(less) }, func(q *Query[int]) *Query[int] { return q.Sort(less) }}
+	}
+	return op{"Reverse", (*Lazy[int]).Reverse, (*Query[int]).Reverse}
+}
+
+func TestLazy_Differential(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		src := make([]int, r.Intn(30))
+		for j := range src {
+			src[j] = r.Intn(40) - 10
+		}
+		l := NewLazy(src)
+		q := NewQuery(src).Clone()
+		var names []string
+		for n := r.Intn(7); n > 0; n-- {
+			o := randomOp(r)
+			names = append(names, o.name)
+			l = o.lazy(l)
+			q = o.eager(q)
+		}
+		want := q.ToSlice()
+		if len(want) == 0 {
+			want = []int{}
+		}
+		if got := l.ToSlice(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v on %v: Lazy.ToSlice() = %v, want %v", names, src, got, want)
+		}
+		if got := collect(compose(l.src, l.stages)); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v on %v: unoptimized = %v, want %v", names, src, got, want)
+		}
+		if got := l.Count(nil); got != len(want) {
+			t.Fatalf("%v on %v: Lazy.Count(nil) = %d, want %d", names, src, got, len(want))
+		}
+		if got, want := l.Count(isEven), NewQuery(want).Count(isEven); got != want {
+			t.Fatalf("%v on %v: Lazy.Count() = %d, want %d", names, src, got, want)
+		}
+		if got, want := l.Any(isEven), NewQuery(want).Any(isEven); got != want {
+			t.Fatalf("%v on %v: Lazy.Any() = %t, want %t", names, src, got, want)
+		}
+		if got, err := l.TryFirst(); err == nil && got != want[0] || err != nil && len(want) > 0 {
+			t.Fatalf("%v on %v: Lazy.TryFirst() = %d, %v, want %v", names, src, got, err, want)
+		}
+	}
+}
+
+func TestLazy_TopK_Stable(t *testing.T) {
+	type pair struct {
+		key, pos int
+	}
+	r := rand.New(rand.NewSource(2))
+	byKey := func(a, b pair) bool {
+		return a.key < b.key
+	}
+	for i := 0; i < 500; i++ {
+		src := make([]pair, r.Intn(50))
+		for j := range src {
+			src[j] = pair{r.Intn(5), j}
+		}
+		k := r.Intn(60)
+		l := NewLazy(src).Sort(byKey).Take(k)
+		want := collect(compose(l.src, l.stages))
+		if got := l.ToSlice(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Sort().Take(%d) on %v = %v, want %v", k, src, got, want)
+		}
+	}
+}
+
+func TestLazy_Count_ShortCircuit(t *testing.T) {
+	calls := 0
+	l := NewLazy([]int{1, 2, 3, 4, 5}).Each(func(e int) int {
+		calls++
+		return e
+	}).Sort(func(a, b int) bool {
+		calls++
+		return a > b
+	}).Skip(1).Take(3)
+	if n := l.Count(nil); n != 3 {
+		t.Errorf("Lazy.Count(nil) = %d, want 3", n)
+	}
+	if calls != 0 {
+		t.Errorf("Lazy.Count(nil) called stage functions %d times, want 0", calls)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"slices"
+)
+
+// An OrderedQuery is a Query with an ordering made of one or more keys.
+//
+// It is created by OrderBy, OrderByDescending or OrderByFunc and refined
+// with ThenBy, ThenByDescending or ThenByFunc. The ordering is applied
+// as a single stable sort when ToQuery or ToSlice is called, so elements
+// that compare equal on every key keep their relative source order.
+//
+// Every refinement returns a new OrderedQuery, and the source Query is
+// never modified.
+type OrderedQuery[E any] struct {
+	q    *Query[E]
+	cmps []func(a, b E) int
+}
+
+// keyCompare returns a comparison function that compares elements by
+// the key returned from key, reversed if desc is true.
+func keyCompare[E any, K cmp.Ordered](key func(E) K, desc bool) func(a, b E) int {
+	if key == nil {
+		return nil
+	}
+	if desc {
+		return func(a, b E) int {
+			return cmp.Compare(key(b), key(a))
+		}
+	}
+	return func(a, b E) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// reverseCompare returns the comparison function c with its order reversed.
+func reverseCompare[E any](c func(a, b E) int) func(a, b E) int {
+	if c == nil {
+		return nil
+	}
+	return func(a, b E) int {
+		return c(b, a)
+	}
+}
+
+// OrderBy orders the elements of the Query in ascending order of the key
+// returned from key.
+func OrderBy[E any, K cmp.Ordered](q *Query[E], key func(E) K) *OrderedQuery[E] {
+	return OrderByFunc(q, keyCompare(key, false))
+}
+
+// OrderByDescending orders the elements of the Query in descending order
+// of the key returned from key.
+func OrderByDescending[E any, K cmp.Ordered](q *Query[E], key func(E) K) *OrderedQuery[E] {
+	return OrderByFunc(q, keyCompare(key, true))
+}
+
+// OrderByFunc orders the elements of the Query using the comparison
+// function c.
+//
+// The function c follows the convention of cmp.Compare: it returns a
+// negative number when a < b, a positive number when a > b and zero
+// when a and b are equal for the purpose of the ordering.
+// A nil c keeps the source order.
+func OrderByFunc[E any](q *Query[E], c func(a, b E) int) *OrderedQuery[E] {
+	return (&OrderedQuery[E]{q: q}).ThenByFunc(c)
+}
+
+// OrderByDescendingFunc orders the elements of the Query in the reverse
+// order of the comparison function c.
+func OrderByDescendingFunc[E any](q *Query[E], c func(a, b E) int) *OrderedQuery[E] {
+	return OrderByFunc(q, reverseCompare(c))
+}
+
+// ThenBy refines the ordering of o: elements that are equal under the
+// previous keys are ordered in ascending order of the key returned
+// from key.
+func ThenBy[E any, K cmp.Ordered](o *OrderedQuery[E], key func(E) K) *OrderedQuery[E] {
+	return o.ThenByFunc(keyCompare(key, false))
+}
+
+// ThenByDescending refines the ordering of o: elements that are equal
+// under the previous keys are ordered in descending order of the key
+// returned from key.
+func ThenByDescending[E any, K cmp.Ordered](o *OrderedQuery[E], key func(E) K) *OrderedQuery[E] {
+	return o.ThenByFunc(keyCompare(key, true))
+}
+
+// ThenByFunc refines the ordering using the comparison function c for
+// elements that are equal under the previous keys.
+//
+// A nil c leaves the ordering unchanged.
+func (o *OrderedQuery[E]) ThenByFunc(c func(a, b E) int) *OrderedQuery[E] {
+	if c == nil {
+		return o
+	}
+	return &OrderedQuery[E]{
+		q:    o.q,
+		cmps: append(slices.Clip(o.cmps), c),
+	}
+}
+
+// ThenByDescendingFunc refines the ordering using the reverse of the
+// comparison function c for elements that are equal under the previous
+// keys.
+func (o *OrderedQuery[E]) ThenByDescendingFunc(c func(a, b E) int) *OrderedQuery[E] {
+	return o.ThenByFunc(reverseCompare(c))
+}
+
+// Compare compares two elements using every key of the ordering in turn.
+//
+// It returns the result of the first key that does not consider a and b
+// equal, or zero if all keys do.
+func (o *OrderedQuery[E]) Compare(a, b E) int {
+	for _, c := range o.cmps {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// Less reports whether a is ordered before b.
+//
+// It can be passed to Query.Sort.
+func (o *OrderedQuery[E]) Less(a, b E) bool {
+	return o.Compare(a, b) < 0
+}
+
+// ToQuery returns the ordered elements as a new Query.
+func (o *OrderedQuery[E]) ToQuery() *Query[E] {
+	return NewQuery(o.ToSlice())
+}
+
+// ToSlice returns the ordered elements as a new slice.
+func (o *OrderedQuery[E]) ToSlice() []E {
+	v := append(make([]E, 0, len(*o.q)), *o.q...)
+	slices.SortStableFunc(v, o.Compare)
+	return v
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type employee struct {
+	Dept string
+	Name string
+	Age  int
+}
+
+var orderEmployees = &Query[employee]{
+	{"ops", "Kim", 40},
+	{"dev", "Bob", 31},
+	{"ops", "Ann", 29},
+	{"dev", "Ann", 25},
+	{"dev", "Bob", 45},
+}
+
+func employeeDept(e employee) string { return e.Dept }
+
+func employeeName(e employee) string { return e.Name }
+
+func employeeAge(e employee) int { return e.Age }
+
+func TestOrderedQuery_ToQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		o    *OrderedQuery[employee]
+		want *Query[employee]
+	}{
+		{
+			name: "nil key keeps order",
+			o:    OrderBy[employee, int](orderEmployees, nil),
+			want: orderEmployees,
+		},
+		{
+			name: "single key is stable",
+			o:    OrderBy(orderEmployees, employeeDept),
+			want: &Query[employee]{
+				{"dev", "Bob", 31},
+				{"dev", "Ann", 25},
+				{"dev", "Bob", 45},
+				{"ops", "Kim", 40},
+				{"ops", "Ann", 29},
+			},
+		},
+		{
+			name: "then by",
+			o:    ThenBy(OrderBy(orderEmployees, employeeDept), employeeName),
+			want: &Query[employee]{
+				{"dev", "Ann", 25},
+				{"dev", "Bob", 31},
+				{"dev", "Bob", 45},
+				{"ops", "Ann", 29},
+				{"ops", "Kim", 40},
+			},
+		},
+		{
+			name: "descending then by descending",
+			o:    ThenByDescending(OrderByDescending(orderEmployees, employeeDept), employeeAge),
+			want: &Query[employee]{
+				{"ops", "Kim", 40},
+				{"ops", "Ann", 29},
+				{"dev", "Bob", 45},
+				{"dev", "Bob", 31},
+				{"dev", "Ann", 25},
+			},
+		},
+		{
+			name: "comparator functions",
+			o: OrderByDescendingFunc(orderEmployees, func(a, b employee) int {
+				return strings.Compare(a.Name, b.Name)
+			}).ThenByFunc(func(a, b employee) int {
+				return a.Age - b.Age
+			}),
+			want: &Query[employee]{
+				{"ops", "Kim", 40},
+				{"dev", "Bob", 31},
+				{"dev", "Bob", 45},
+				{"dev", "Ann", 25},
+				{"ops", "Ann", 29},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.ToQuery(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OrderedQuery.ToQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := orderEmployees.First(); got.Name != "Kim" {
+		t.Errorf("source modified, first element is %v", got)
+	}
+}
+
+func TestOrderedQuery_Less(t *testing.T) {
+	o := ThenBy(OrderBy(orderEmployees, employeeName), employeeAge)
+	q := NewQuery(append([]employee{}, *orderEmployees...)).Sort(o.Less)
+	if !q.Equal(o.ToSlice(), func(a, b employee) bool { return a == b }) {
+		t.Errorf("Query.Sort(OrderedQuery.Less) = %v, want %v", q, o.ToSlice())
+	}
+}
+
+func TestOrderedQuery_Immutable(t *testing.T) {
+	base := OrderBy(orderEmployees, employeeDept)
+	_ = ThenBy(base, employeeAge)
+	if got, want := len(base.cmps), 1; got != want {
+		t.Errorf("len(base.cmps) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// A WindowSpec describes the windows that window functions such as
+// RowNumber, Rank, Lag or WindowSum compute their values over, like
+// the OVER clause of SQL.
+//
+// The elements of the Query are split into partitions by the key given
+// to PartitionBy, or form a single partition without one. Within every
+// partition, the elements are ordered by the comparison functions given
+// to OrderByFunc, or keep their order in the Query without one.
+//
+// Window functions return a new Query that pairs every element with
+// its computed value, in the order of the source Query, which is never
+// modified. Every refinement of a WindowSpec returns a new WindowSpec.
+type WindowSpec[E any] struct {
+	q *Query[E]
+	// partition returns the indexes of the elements of every partition,
+	// or nil for a single partition.
+	partition func(v []E) [][]int
+	cmps      []func(a, b E) int
+}
+
+// Over returns a WindowSpec with a single partition holding every
+// element of the Query in its order.
+func Over[E any](q *Query[E]) *WindowSpec[E] {
+	return &WindowSpec[E]{q: q}
+}
+
+// PartitionBy returns a copy of the WindowSpec whose partitions hold
+// the elements with the same key, as computed by key. It replaces the
+// partitions of the WindowSpec.
+//
+// A nil key puts every element into a single partition.
+func PartitionBy[E any, K comparable](w *WindowSpec[E], key func(E) K) *WindowSpec[E] {
+	r := &WindowSpec[E]{q: w.q, cmps: w.cmps}
+	if key == nil {
+		return r
+	}
+	r.partition = func(v []E) [][]int {
+		var parts [][]int
+		index := make(map[K]int)
+		for i, e := range v {
+			k := key(e)
+			p, ok := index[k]
+			if !ok {
+				p = len(parts)
+				index[k] = p
+				parts = append(parts, nil)
+			}
+			parts[p] = append(parts[p], i)
+		}
+		return parts
+	}
+	return r
+}
+
+// OrderByFunc returns a copy of the WindowSpec that orders the elements
+// of every partition using the comparison function c, for elements
+// that are equal under the comparison functions given before, like
+// OrderedQuery.ThenByFunc. The ordering is stable.
+//
+// Elements that are equal under every comparison function are peers,
+// which share their Rank and DenseRank. The Compare method of a Field
+// or an OrderedQuery can be passed as c.
+//
+// A nil c leaves the ordering unchanged.
+func (w *WindowSpec[E]) OrderByFunc(c func(a, b E) int) *WindowSpec[E] {
+	if c == nil {
+		return w
+	}
+	return &WindowSpec[E]{
+		q:         w.q,
+		partition: w.partition,
+		cmps:      append(slices.Clip(w.cmps), c),
+	}
+}
+
+// OrderByDescendingFunc returns a copy of the WindowSpec that orders the
+// elements of every partition using the reverse of the comparison
+// function c, like OrderByFunc.
+func (w *WindowSpec[E]) OrderByDescendingFunc(c func(a, b E) int) *WindowSpec[E] {
+	return w.OrderByFunc(reverseCompare(c))
+}
+
+// compare compares two elements using every comparison function of the
+// ordering in turn, like OrderedQuery.Compare.
+func (w *WindowSpec[E]) compare(a, b E) int {
+	for _, c := range w.cmps {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// RowNumber numbers the elements of every partition in their order,
+// starting at 1.
+func (w *WindowSpec[E]) RowNumber() *Query[Pair[E, int]] {
+	return windowOf(w, func(part []E, vals []int) {
+		for j := range part {
+			vals[j] = j + 1
+		}
+	})
+}
+
+// Rank ranks the elements of every partition in their order, starting
+// at 1. Peers share the rank of the first of them, and the rank after
+// them skips as many ranks as there were peers, so ranks of 1, 1, 3 are
+// assigned to three elements of which the first two are peers.
+//
+// Without an ordering, every element of a partition is ranked 1.
+func (w *WindowSpec[E]) Rank() *Query[Pair[E, int]] {
+	return windowOf(w, func(part []E, vals []int) {
+		for j := range part {
+			if j > 0 && w.compare(part[j-1], part[j]) == 0 {
+				vals[j] = vals[j-1]
+			} else {
+				vals[j] = j + 1
+			}
+		}
+	})
+}
+
+// DenseRank ranks the elements of every partition like Rank, but
+// without gaps after peers, so ranks of 1, 1, 2 are assigned to three
+// elements of which the first two are peers.
+func (w *WindowSpec[E]) DenseRank() *Query[Pair[E, int]] {
+	return windowOf(w, func(part []E, vals []int) {
+		for j := range part {
+			switch {
+			case j == 0:
+				vals[j] = 1
+			case w.compare(part[j-1], part[j]) == 0:
+				vals[j] = vals[j-1]
+			default:
+				vals[j] = vals[j-1] + 1
+			}
+		}
+	})
+}
+
+// NTile splits every partition in its order into n buckets of as equal
+// size as possible, and numbers every element with its bucket, starting
+// at 1. Buckets that hold an extra element come first. A partition of
+// fewer than n elements has a bucket for every element.
+//
+// An n less than 1 puts every element into a single bucket.
+func (w *WindowSpec[E]) NTile(n int) *Query[Pair[E, int]] {
+	n = max(n, 1)
+	return windowOf(w, func(part []E, vals []int) {
+		size, extra := len(part)/n, len(part)%n
+		// The first extra buckets hold size+1 elements.
+		big := extra * (size + 1)
+		for j := range part {
+			if j < big {
+				vals[j] = j/(size+1) + 1
+			} else {
+				vals[j] = extra + (j-big)/size + 1
+			}
+		}
+	})
+}
+
+// Lag pairs every element with the value, as computed by value, of the
+// element n rows before it in its partition, or def if there is none,
+// such as for period-over-period deltas. A negative n looks after the
+// element, like Lead.
+//
+// A nil value yields an empty Query.
+func Lag[E, V any](w *WindowSpec[E], value func(E) V, n int, def V) *Query[Pair[E, V]] {
+	if value == nil {
+		return &Query[Pair[E, V]]{}
+	}
+	// No partition has that many rows, and -n must not overflow.
+	n = max(n, -math.MaxInt)
+	return windowOf(w, func(part []E, vals []V) {
+		for j := range part {
+			// Compare rather than subtract, so that n cannot overflow.
+			if (n >= 0 && j >= n) || (n < 0 && len(part)-j > -n) {
+				vals[j] = value(part[j-n])
+			} else {
+				vals[j] = def
+			}
+		}
+	})
+}
+
+// Lead pairs every element with the value, as computed by value, of
+// the element n rows after it in its partition, or def if there is
+// none. A negative n looks before the element, like Lag.
+//
+// A nil value yields an empty Query.
+func Lead[E, V any](w *WindowSpec[E], value func(E) V, n int, def V) *Query[Pair[E, V]] {
+	return Lag(w, value, -max(n, -math.MaxInt), def)
+}
+
+// UnboundedPreceding and UnboundedFollowing are the offsets in a Frame
+// of the first and the last row of the partition.
+const (
+	UnboundedPreceding = math.MinInt
+	UnboundedFollowing = math.MaxInt
+)
+
+// A Frame selects the rows of a partition that a window aggregate such
+// as WindowSum combines for every row, like the ROWS BETWEEN clause of
+// SQL: the rows from Start to End, both given as offsets from the
+// current row, where negative offsets count the rows before it and
+// positive ones the rows after it. The frame is cut off at the ends of
+// the partition, and may be empty, like the frame of the first row of
+// Frame{Start: -3, End: -1}.
+//
+// The zero Frame holds just the current row. The Frame of a running
+// total, as of ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW, is
+// Frame{Start: UnboundedPreceding}, a centered moving window of five
+// rows is Frame{Start: -2, End: 2}, and the three rows before the
+// current one are Frame{Start: -3, End: -1}.
+//
+// The window aggregates panic with an error wrapping ErrInvalidFrame if
+// Start is greater than End.
+type Frame struct {
+	Start, End int
+}
+
+// check panics if the frame is invalid, naming the operation op.
+func (f Frame) check(op string) {
+	if f.Start > f.End {
+		panic(fmt.Errorf("sliceql.%s: %w", op, ErrInvalidFrame))
+	}
+}
+
+// bounds returns the first and the last row of the frame of row j in a
+// partition of n rows. The frame is empty if lo is greater than hi.
+//
+// The offsets are clamped before they are added to j, so that the
+// unbounded ones do not overflow.
+func (f Frame) bounds(j, n int) (lo, hi int) {
+	return j + min(max(f.Start, -j), n-j), j + max(min(f.End, n-1-j), -j-1)
+}
+
+// WindowFold pairs every element with the result of combining the
+// elements of its frame with f, in the order of the partition, starting
+// with seed, like Aggregate. Elements with an empty frame are paired
+// with seed.
+//
+// WindowFold calls f for every element of every frame, which takes
+// time proportional to the number of elements times the size of their
+// frames. WindowMin, WindowMax and WindowSum of integer values take
+// linear time for any frame instead. A nil f yields an empty Query.
+func WindowFold[E, A any](w *WindowSpec[E], frame Frame, seed A, f func(A, E) A) *Query[Pair[E, A]] {
+	if f == nil {
+		return &Query[Pair[E, A]]{}
+	}
+	frame.check("WindowFold")
+	return windowOf(w, func(part []E, vals []A) {
+		for j := range part {
+			lo, hi := frame.bounds(j, len(part))
+			acc := seed
+			for _, e := range part[lo:max(lo, hi+1)] {
+				acc = f(acc, e)
+			}
+			vals[j] = acc
+		}
+	})
+}
+
+// WindowSum pairs every element with the sum of the values, as computed
+// by value, of the elements of its frame. The sum of an empty frame is
+// zero.
+//
+// Integer values are summed in linear time for any frame. Floating-point
+// values are summed as a running total for frames that start at the
+// first row of the partition, and frame by frame otherwise, since
+// subtracting the values that leave the frame would lose precision.
+// A nil value yields an empty Query.
+func WindowSum[E any, N Number](w *WindowSpec[E], frame Frame, value func(E) N) *Query[Pair[E, N]] {
+	if value == nil {
+		return &Query[Pair[E, N]]{}
+	}
+	frame.check("WindowSum")
+	return windowOf(w, func(part []E, vals []N) {
+		frameSums(part, frame, value, vals)
+	})
+}
+
+// WindowAverage pairs every element with the arithmetic mean of the
+// values, as computed by value, of the elements of its frame. The mean
+// of an empty frame is NaN. The values are summed as float64 values,
+// like the floating-point values of WindowSum.
+//
+// A nil value yields an empty Query.
+func WindowAverage[E any, N Number](w *WindowSpec[E], frame Frame, value func(E) N) *Query[Pair[E, float64]] {
+	if value == nil {
+		return &Query[Pair[E, float64]]{}
+	}
+	frame.check("WindowAverage")
+	return windowOf(w, func(part []E, vals []float64) {
+		sums := make([]float64, len(part))
+		frameSums(part, frame, func(e E) float64 {
+			return float64(value(e))
+		}, sums)
+		for j := range part {
+			lo, hi := frame.bounds(j, len(part))
+			if lo > hi {
+				vals[j] = math.NaN()
+				continue
+			}
+			vals[j] = sums[j] / float64(hi-lo+1)
+		}
+	})
+}
+
+// frameSums stores the sum of the values of the frame of every row of
+// the partition part in sums.
+func frameSums[E any, N Number](part []E, frame Frame, value func(E) N, sums []N) {
+	v := make([]N, len(part))
+	for j, e := range part {
+		v[j] = value(e)
+	}
+	if integer[N]() {
+		// Integer arithmetic is exact, even where it overflows, so the
+		// sum of a frame is the difference of two prefix sums.
+		prefix := make([]N, len(part)+1)
+		for j, x := range v {
+			prefix[j+1] = prefix[j] + x
+		}
+		for j := range part {
+			lo, hi := frame.bounds(j, len(part))
+			sums[j] = prefix[max(lo, hi+1)] - prefix[lo]
+		}
+		return
+	}
+	if frame.Start <= -(len(part) - 1) {
+		// Every frame starts at the first row, so a running total
+		// gives the sums without subtracting, which would lose
+		// precision for floating-point values.
+		var total N
+		next := 0
+		for j := range part {
+			_, hi := frame.bounds(j, len(part))
+			for ; next <= hi; next++ {
+				total += v[next]
+			}
+			sums[j] = total
+		}
+		return
+	}
+	for j := range part {
+		lo, hi := frame.bounds(j, len(part))
+		var sum N
+		for _, x := range v[lo:max(lo, hi+1)] {
+			sum += x
+		}
+		sums[j] = sum
+	}
+}
+
+// integer reports whether N is an integer type.
+func integer[N Number]() bool {
+	var one N = 1
+	return one/2 == 0
+}
+
+// WindowMin pairs every element with the smallest key, as computed by
+// key, of the elements of its frame. Keys are compared like
+// cmp.Compare. Elements with an empty frame are paired with the zero
+// value of K.
+//
+// A nil key yields an empty Query.
+func WindowMin[E any, K cmp.Ordered](w *WindowSpec[E], frame Frame, key func(E) K) *Query[Pair[E, K]] {
+	return frameExtremes(w, "WindowMin", frame, key, cmp.Less[K])
+}
+
+// WindowMax pairs every element with the largest key, as computed by
+// key, of the elements of its frame. Keys are compared like
+// cmp.Compare. Elements with an empty frame are paired with the zero
+// value of K.
+//
+// A nil key yields an empty Query.
+func WindowMax[E any, K cmp.Ordered](w *WindowSpec[E], frame Frame, key func(E) K) *Query[Pair[E, K]] {
+	return frameExtremes(w, "WindowMax", frame, key, func(a, b K) bool {
+		return cmp.Less(b, a)
+	})
+}
+
+// frameExtremes pairs every element with the key of its frame that no
+// other key of the frame beats according to better, for the operation
+// op.
+//
+// Since the frames of consecutive rows only move forward, the rows that
+// may still become the best one of a later frame are kept in a deque,
+// best first, which takes linear time for every partition.
+func frameExtremes[E any, K cmp.Ordered](w *WindowSpec[E], op string, frame Frame, key func(E) K, better func(a, b K) bool) *Query[Pair[E, K]] {
+	if key == nil {
+		return &Query[Pair[E, K]]{}
+	}
+	frame.check(op)
+	return windowOf(w, func(part []E, vals []K) {
+		keys := make([]K, len(part))
+		for j, e := range part {
+			keys[j] = key(e)
+		}
+		var deque []int
+		next := 0
+		for j := range part {
+			lo, hi := frame.bounds(j, len(part))
+			for ; next <= hi; next++ {
+				for len(deque) > 0 && !better(keys[deque[len(deque)-1]], keys[next]) {
+					deque = deque[:len(deque)-1]
+				}
+				deque = append(deque, next)
+			}
+			for len(deque) > 0 && deque[0] < lo {
+				deque = deque[1:]
+			}
+			if len(deque) > 0 {
+				vals[j] = keys[deque[0]]
+			}
+		}
+	})
+}
+
+// windowOf computes the values of a window function. It calls f with
+// the elements of every partition of w in their order, and f stores the
+// value of every element in vals. The elements are paired with their
+// values in the order of the source Query.
+func windowOf[E, V any](w *WindowSpec[E], f func(part []E, vals []V)) *Query[Pair[E, V]] {
+	v := *w.q
+	result := Query[Pair[E, V]](make([]Pair[E, V], len(v)))
+	var parts [][]int
+	if w.partition != nil {
+		parts = w.partition(v)
+	} else if len(v) > 0 {
+		parts = [][]int{make([]int, len(v))}
+		for i := range v {
+			parts[0][i] = i
+		}
+	}
+	for _, rows := range parts {
+		if len(w.cmps) > 0 {
+			slices.SortStableFunc(rows, func(i, j int) int {
+				return w.compare(v[i], v[j])
+			})
+		}
+		part := make([]E, len(rows))
+		for j, i := range rows {
+			part[j] = v[i]
+		}
+		vals := make([]V, len(rows))
+		f(part, vals)
+		for j, i := range rows {
+			result[i] = Pair[E, V]{First: v[i], Second: vals[j]}
+		}
+	}
+	return &result
+}
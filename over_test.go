@@ -0,0 +1,368 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type score struct {
+	team   string
+	player string
+	points int
+}
+
+var scores = Query[score]{
+	{"red", "ann", 30},
+	{"blue", "bob", 20},
+	{"red", "cid", 50},
+	{"blue", "dan", 20},
+	{"red", "eve", 30},
+	{"blue", "fay", 10},
+	{"red", "gus", 10},
+}
+
+func byTeam(s score) string {
+	return s.team
+}
+
+func byPoints(a, b score) int {
+	return cmp.Compare(a.points, b.points)
+}
+
+// seconds returns the computed values of the pairs in q.
+func seconds[E, V any](q *Query[Pair[E, V]]) []V {
+	v := make([]V, len(*q))
+	for i, p := range *q {
+		v[i] = p.Second
+	}
+	return v
+}
+
+func TestWindowSpec_Ranking(t *testing.T) {
+	leaderboard := PartitionBy(Over(&scores), byTeam).OrderByDescendingFunc(byPoints)
+	tests := []struct {
+		name string
+		got  *Query[Pair[score, int]]
+		want []int
+	}{
+		{
+			name: "row number without partition or ordering",
+			got:  Over(&scores).RowNumber(),
+			want: []int{1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name: "row number",
+			got:  leaderboard.RowNumber(),
+			want: []int{2, 1, 1, 2, 3, 3, 4},
+		},
+		{
+			name: "rank",
+			got:  leaderboard.Rank(),
+			want: []int{2, 1, 1, 1, 2, 3, 4},
+		},
+		{
+			name: "dense rank",
+			got:  leaderboard.DenseRank(),
+			want: []int{2, 1, 1, 1, 2, 2, 3},
+		},
+		{
+			name: "rank without ordering",
+			got:  PartitionBy(Over(&scores), byTeam).Rank(),
+			want: []int{1, 1, 1, 1, 1, 1, 1},
+		},
+		{
+			name: "ntile",
+			got:  Over(&scores).OrderByFunc(byPoints).NTile(3),
+			want: []int{2, 1, 3, 2, 3, 1, 1},
+		},
+		{
+			name: "ntile with more buckets than elements",
+			got:  leaderboard.NTile(10),
+			want: []int{2, 1, 1, 2, 3, 3, 4},
+		},
+		{
+			name: "ntile with zero buckets",
+			got:  leaderboard.NTile(0),
+			want: []int{1, 1, 1, 1, 1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := seconds(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+			for i, p := range *tt.got {
+				if p.First != scores[i] {
+					t.Fatalf("element %d = %v, want %v", i, p.First, scores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWindowSpec_NTile(t *testing.T) {
+	// Buckets differ in size by at most one, and the larger ones come
+	// first.
+	for n := 1; n <= 6; n++ {
+		for m := 0; m <= 20; m++ {
+			got := seconds(Over(Create(m, func(i int) int { return i })).NTile(n))
+			sizes := make(map[int]int)
+			for i, b := range got {
+				if i > 0 && b != got[i-1] && b != got[i-1]+1 {
+					t.Fatalf("NTile(%d) of %d elements = %v, want consecutive buckets", n, m, got)
+				}
+				sizes[b]++
+			}
+			for b := 1; b < len(sizes); b++ {
+				if d := sizes[b] - sizes[b+1]; d < 0 || d > 1 {
+					t.Fatalf("NTile(%d) of %d elements = %v, want buckets of equal size, larger first", n, m, got)
+				}
+			}
+			if want := min(n, m); len(sizes) != want {
+				t.Fatalf("NTile(%d) of %d elements has %d buckets, want %d", n, m, len(sizes), want)
+			}
+		}
+	}
+}
+
+func Test_LagLead(t *testing.T) {
+	w := PartitionBy(Over(&scores), byTeam)
+	points := func(s score) int {
+		return s.points
+	}
+	tests := []struct {
+		name string
+		got  *Query[Pair[score, int]]
+		want []int
+	}{
+		{
+			name: "lag",
+			got:  Lag(w, points, 1, -1),
+			want: []int{-1, -1, 30, 20, 50, 20, 30},
+		},
+		{
+			name: "lag by two",
+			got:  Lag(w, points, 2, -1),
+			want: []int{-1, -1, -1, -1, 30, 20, 50},
+		},
+		{
+			name: "lead",
+			got:  Lead(w, points, 1, -1),
+			want: []int{50, 20, 30, 10, 10, -1, -1},
+		},
+		{
+			name: "negative lag",
+			got:  Lag(w, points, -1, -1),
+			want: []int{50, 20, 30, 10, 10, -1, -1},
+		},
+		{
+			name: "zero offset",
+			got:  Lead(w, points, 0, -1),
+			want: []int{30, 20, 50, 20, 30, 10, 10},
+		},
+		{
+			name: "offset beyond partitions",
+			got:  Lag(w, points, math.MinInt, -1),
+			want: []int{-1, -1, -1, -1, -1, -1, -1},
+		},
+		{
+			name: "lead beyond partitions",
+			got:  Lead(w, points, math.MinInt, -1),
+			want: []int{-1, -1, -1, -1, -1, -1, -1},
+		},
+		{
+			name: "ordered lag",
+			got:  Lag(Over(&scores).OrderByFunc(byPoints), points, 1, 0),
+			want: []int{20, 10, 30, 20, 30, 0, 10},
+		},
+		{
+			name: "nil value",
+			got:  Lag[score, int](w, nil, 1, -1),
+			want: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := seconds(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WindowAggregates(t *testing.T) {
+	w := PartitionBy(Over(&scores), byTeam)
+	points := func(s score) int {
+		return s.points
+	}
+	running := Frame{Start: UnboundedPreceding}
+	moving := Frame{Start: -1, End: 1}
+	before := Frame{Start: -2, End: -1}
+	if got, want := seconds(WindowSum(w, running, points)), []int{30, 20, 80, 40, 110, 50, 120}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowSum() running = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowSum(w, moving, points)), []int{80, 40, 110, 50, 90, 30, 40}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowSum() moving = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowSum(w, Frame{}, points)), []int{30, 20, 50, 20, 30, 10, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowSum() of current row = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowSum(w, before, points)), []int{0, 0, 30, 20, 80, 40, 80}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowSum() of preceding rows = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowFold(w, before, -1, func(acc int, s score) int {
+		return max(acc, s.points)
+	})), []int{-1, -1, 30, 20, 50, 20, 50}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowFold() of preceding rows = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowAverage(w, moving, points)), []float64{40, 20, 110.0 / 3, 50.0 / 3, 30, 15, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowAverage() moving = %v, want %v", got, want)
+	}
+	if got := seconds(WindowAverage(w, before, points)); !math.IsNaN(got[0]) || got[4] != 40 {
+		t.Errorf("WindowAverage() of preceding rows = %v, want NaN at 0 and 40 at 4", got)
+	}
+	if got, want := seconds(WindowMin(w, running, points)), []int{30, 20, 30, 20, 30, 10, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowMin() running = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowMax(w, Frame{End: UnboundedFollowing}, points)), []int{50, 20, 50, 20, 30, 10, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowMax() to the end = %v, want %v", got, want)
+	}
+	if got, want := seconds(WindowMax(w, Frame{Start: 1, End: UnboundedFollowing}, points)), []int{50, 20, 30, 10, 10, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowMax() of following rows = %v, want %v", got, want)
+	}
+	if got := WindowSum[score, int](w, running, nil); len(*got) != 0 {
+		t.Errorf("WindowSum(nil) = %v, want []", got)
+	}
+	if got := WindowMin[score, int](w, running, nil); len(*got) != 0 {
+		t.Errorf("WindowMin(nil) = %v, want []", got)
+	}
+	if got := WindowFold[score, int](w, running, 0, nil); len(*got) != 0 {
+		t.Errorf("WindowFold(nil) = %v, want []", got)
+	}
+	if got := Over(&Query[int]{}).RowNumber(); *got == nil || len(*got) != 0 {
+		t.Errorf("RowNumber() on empty Query = %#v, want an empty Query", got)
+	}
+}
+
+func Test_WindowAggregates_Differential(t *testing.T) {
+	// The window aggregates compute the same values as folding every
+	// frame, for random frames, partitions and orderings.
+	r := rand.New(rand.NewSource(11))
+	offsets := []int{UnboundedPreceding, -5, -2, -1, 0, 1, 2, 5, UnboundedFollowing}
+	for i := 0; i < 500; i++ {
+		q := Create(r.Intn(30), func(int) int {
+			return r.Intn(21) - 10
+		})
+		w := Over(q)
+		if r.Intn(2) == 0 {
+			m := r.Intn(3) + 1
+			w = PartitionBy(w, func(e int) int {
+				return (e + 10) % m
+			})
+		}
+		if r.Intn(2) == 0 {
+			w = w.OrderByFunc(func(a, b int) int {
+				return cmp.Compare(a/3, b/3)
+			})
+		}
+		frame := Frame{Start: offsets[r.Intn(len(offsets))], End: offsets[r.Intn(len(offsets))]}
+		if frame.Start > frame.End {
+			frame.Start, frame.End = frame.End, frame.Start
+		}
+		sum := seconds(WindowFold(w, frame, 0, func(acc, e int) int {
+			return acc + e
+		}))
+		if got := seconds(WindowSum(w, frame, identity[int])); !reflect.DeepEqual(got, sum) {
+			t.Fatalf("WindowSum(%v) on %v = %v, want %v", frame, q, got, sum)
+		}
+		// Prefix sums stay exact where small integers overflow.
+		wide := func(e int) int8 {
+			return int8(e * 12)
+		}
+		sum8 := seconds(WindowFold(w, frame, int8(0), func(acc int8, e int) int8 {
+			return acc + wide(e)
+		}))
+		if got := seconds(WindowSum(w, frame, wide)); !reflect.DeepEqual(got, sum8) {
+			t.Fatalf("WindowSum(%v) of int8 on %v = %v, want %v", frame, q, got, sum8)
+		}
+		// Empty frames keep the seed, where WindowMin and WindowMax
+		// give zero.
+		lo := seconds(WindowFold(w, frame, math.MaxInt, func(acc, e int) int {
+			return min(acc, e)
+		}))
+		n := seconds(WindowFold(w, frame, 0, func(acc, _ int) int {
+			return acc + 1
+		}))
+		for j := range n {
+			if n[j] == 0 {
+				lo[j] = 0
+			}
+		}
+		if got := seconds(WindowMin(w, frame, identity[int])); !reflect.DeepEqual(got, lo) {
+			t.Fatalf("WindowMin(%v) on %v = %v, want %v", frame, q, got, lo)
+		}
+		hi := seconds(WindowFold(w, frame, math.MinInt, func(acc, e int) int {
+			return max(acc, e)
+		}))
+		for j := range n {
+			if n[j] == 0 {
+				hi[j] = 0
+			}
+		}
+		if got := seconds(WindowMax(w, frame, identity[int])); !reflect.DeepEqual(got, hi) {
+			t.Fatalf("WindowMax(%v) on %v = %v, want %v", frame, q, got, hi)
+		}
+		avg := seconds(WindowAverage(w, frame, identity[int]))
+		for j := range avg {
+			if n[j] == 0 {
+				if !math.IsNaN(avg[j]) {
+					t.Fatalf("WindowAverage(%v) on %v = %v, want NaN at %d", frame, q, avg[j], j)
+				}
+				continue
+			}
+			if want := float64(sum[j]) / float64(n[j]); math.Abs(avg[j]-want) > 1e-9 {
+				t.Fatalf("WindowAverage(%v) on %v = %v, want %v at %d", frame, q, avg[j], want, j)
+			}
+		}
+	}
+}
+
+func Test_Frame_Invalid(t *testing.T) {
+	w := Over(&scores)
+	points := func(s score) int {
+		return s.points
+	}
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"WindowFold", func() {
+			WindowFold(w, Frame{Start: 1}, 0, func(acc int, _ score) int { return acc })
+		}},
+		{"WindowSum", func() { WindowSum(w, Frame{Start: 1}, points) }},
+		{"WindowAverage", func() { WindowAverage(w, Frame{Start: -1, End: -2}, points) }},
+		{"WindowMin", func() { WindowMin(w, Frame{Start: UnboundedFollowing}, points) }},
+		{"WindowMax", func() { WindowMax(w, Frame{End: UnboundedPreceding}, points) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrInvalidFrame) || !strings.HasPrefix(err.Error(), "sliceql."+tt.name+":") {
+					t.Errorf("%s() recovered %v, want %v", tt.name, err, ErrInvalidFrame)
+				}
+			}()
+			tt.f()
+			t.Errorf("%s() did not panic", tt.name)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A ParallelQuery runs the functions passed to its methods on the
+// elements of a Query concurrently, using a pool of workers.
+//
+// The elements are split into chunks that the workers take in turn.
+// Where keeps the order of the elements unless Unordered is used, and
+// the other methods return the same results as the methods of Query of
+// the same name. The functions passed to the methods must be safe for
+// concurrent use. If a function panics, the remaining chunks are skipped
+// and the panic is raised again in the goroutine that called the method.
+type ParallelQuery[E any] struct {
+	q         *Query[E]
+	workers   int
+	unordered bool
+}
+
+// Parallel returns a ParallelQuery over the elements of the Query that
+// uses the given number of workers. A workers count less than 1 uses
+// one worker per CPU, as reported by runtime.GOMAXPROCS.
+//
+// Like the methods of Query, the methods of the ParallelQuery modify
+// the Query in place.
+func (q *Query[E]) Parallel(workers int) *ParallelQuery[E] {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &ParallelQuery[E]{q: q, workers: workers}
+}
+
+// Unordered returns a copy of the ParallelQuery whose Where does not keep
+// the order of the elements. The elements each chunk keeps are appended
+// to the result as soon as the chunk is done, rather than merged in
+// order at the end.
+func (p *ParallelQuery[E]) Unordered() *ParallelQuery[E] {
+	return &ParallelQuery[E]{q: p.q, workers: p.workers, unordered: true}
+}
+
+// Query returns the Query the ParallelQuery works on.
+func (p *ParallelQuery[E]) Query() *Query[E] {
+	return p.q
+}
+
+// All reports whether every element satisfies f, like Query.All.
+//
+// The workers stop as soon as an element does not satisfy f.
+func (p *ParallelQuery[E]) All(f func(E) bool) bool {
+	if len(*p.q) < 1 || f == nil {
+		return false
+	}
+	var failed atomic.Bool
+	p.run(func(v []E, _ int) bool {
+		for _, e := range v {
+			if !f(e) {
+				failed.Store(true)
+				return false
+			}
+		}
+		return !failed.Load()
+	})
+	return !failed.Load()
+}
+
+// Any reports whether any element satisfies f, like Query.Any.
+//
+// The workers stop as soon as an element satisfies f.
+func (p *ParallelQuery[E]) Any(f func(E) bool) bool {
+	if f == nil {
+		return false
+	}
+	var found atomic.Bool
+	p.run(func(v []E, _ int) bool {
+		for _, e := range v {
+			if f(e) {
+				found.Store(true)
+				return false
+			}
+		}
+		return !found.Load()
+	})
+	return found.Load()
+}
+
+// Count returns the number of elements that satisfy f, like Query.Count.
+func (p *ParallelQuery[E]) Count(f func(E) bool) int {
+	var total atomic.Int64
+	p.run(func(v []E, _ int) bool {
+		n := 0
+		for _, e := range v {
+			if f(e) {
+				n++
+			}
+		}
+		total.Add(int64(n))
+		return true
+	})
+	return int(total.Load())
+}
+
+// Each replaces every element with the result of f, like Query.Each.
+//
+// Returns the ParallelQuery itself.
+func (p *ParallelQuery[E]) Each(f func(E) E) *ParallelQuery[E] {
+	p.run(func(v []E, _ int) bool {
+		for i, e := range v {
+			v[i] = f(e)
+		}
+		return true
+	})
+	p.q.touch()
+	return p
+}
+
+// Where keeps only the elements that satisfy f, like Query.Where.
+//
+// The elements keep their order unless the ParallelQuery is unordered.
+// Returns the ParallelQuery itself, or like Query.Where a ParallelQuery
+// over a new empty Query if the Query is empty or f is nil.
+func (p *ParallelQuery[E]) Where(f func(E) bool) *ParallelQuery[E] {
+	if len(*p.q) < 1 || f == nil {
+		return &ParallelQuery[E]{q: &Query[E]{}, workers: p.workers, unordered: p.unordered}
+	}
+	// Every chunk collects the elements it keeps into its own part,
+	// and the parts are joined in order. Unordered, the chunks append
+	// their elements to the result as they finish.
+	var (
+		mu     sync.Mutex
+		result []E
+		parts  = make([][]E, p.chunks())
+	)
+	p.run(func(v []E, chunk int) bool {
+		var kept []E
+		for _, e := range v {
+			if f(e) {
+				kept = append(kept, e)
+			}
+		}
+		if p.unordered {
+			mu.Lock()
+			defer mu.Unlock()
+			result = append(result, kept...)
+		} else {
+			parts[chunk] = kept
+		}
+		return true
+	})
+	if !p.unordered {
+		n := 0
+		for _, part := range parts {
+			n += len(part)
+		}
+		result = make([]E, 0, n)
+		for _, part := range parts {
+			result = append(result, part...)
+		}
+	}
+	if result == nil {
+		result = make([]E, 0)
+	}
+	*p.q = result
+	p.q.touch()
+	return p
+}
+
+// chunks returns the number of chunks the elements are split into:
+// a few per worker, so that workers that finish early can take over
+// the work of slower ones.
+func (p *ParallelQuery[E]) chunks() int {
+	return min(len(*p.q), p.workers*4)
+}
+
+// run calls f for every chunk of the elements, passing the chunk and
+// its number, on the workers of the ParallelQuery. The workers stop
+// taking chunks once f returns false. If f panics, run panics with the
+// same value after every worker has stopped.
+func (p *ParallelQuery[E]) run(f func(v []E, chunk int) bool) {
+	v := *p.q
+	chunks := p.chunks()
+	if chunks == 0 {
+		return
+	}
+	size := (len(v) + chunks - 1) / chunks
+	var (
+		next    atomic.Int64
+		stop    atomic.Bool
+		wg      sync.WaitGroup
+		once    sync.Once
+		failure any
+		failed  bool
+	)
+	worker := func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				once.Do(func() {
+					failure, failed = r, true
+				})
+				stop.Store(true)
+			}
+		}()
+		for !stop.Load() {
+			chunk := int(next.Add(1) - 1)
+			lo := chunk * size
+			if lo >= len(v) {
+				return
+			}
+			if !f(v[lo:min(lo+size, len(v))], chunk) {
+				stop.Store(true)
+			}
+		}
+	}
+	workers := min(p.workers, chunks)
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+	wg.Wait()
+	if failed {
+		panic(failure)
+	}
+}
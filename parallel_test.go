@@ -0,0 +1,98 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParallelQuery_Differential(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	square := func(e int) int {
+		return e * e % 1000
+	}
+	for i := 0; i < 200; i++ {
+		v := make([]int, r.Intn(200))
+		for j := range v {
+			v[j] = r.Intn(100) - 20
+		}
+		workers := r.Intn(6)
+		m := r.Intn(5) + 1
+		f := func(e int) bool {
+			return e%m == 0
+		}
+		q := NewQuery(v)
+		if got, want := q.Parallel(workers).Count(f), q.Count(f); got != want {
+			t.Fatalf("Count() on %v = %d, want %d", v, got, want)
+		}
+		if got, want := q.Parallel(workers).Any(f), q.Any(f); got != want {
+			t.Fatalf("Any() on %v = %t, want %t", v, got, want)
+		}
+		if got, want := q.Parallel(workers).All(f), q.All(f); got != want {
+			t.Fatalf("All() on %v = %t, want %t", v, got, want)
+		}
+
+		want := q.Clone().Each(square).Where(f).ToSlice()
+		got := q.Clone().Parallel(workers).Each(square).Where(f).Query().ToSlice()
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Each().Where() on %v = %v, want %v", v, got, want)
+		}
+		got = q.Clone().Parallel(workers).Unordered().Where(f).Query().ToSlice()
+		want = q.Clone().Where(f).ToSlice()
+		slices.Sort(got)
+		slices.Sort(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unordered().Where() on %v = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestParallelQuery_Where(t *testing.T) {
+	q := NewQuery([]int{1, 2, 3, 4, 5, 6})
+	p := q.Parallel(2)
+	if got := p.Where(isEven); got != p || !reflect.DeepEqual(q.ToSlice(), []int{2, 4, 6}) {
+		t.Errorf("Where() = %v, want the receiver over [2 4 6]", got.Query())
+	}
+	if got := p.Where(nil).Query(); got == q || len(*got) != 0 {
+		t.Errorf("Where(nil) = %v, want a new empty Query", got)
+	}
+	if got := p.Where(func(int) bool { return false }).Query(); got != q || *got == nil || len(*got) != 0 {
+		t.Errorf("Where() = %#v, want the receiver with no elements", got)
+	}
+	if got := NewQuery([]int{}).Parallel(0).Count(isEven); got != 0 {
+		t.Errorf("Count() on empty Query = %d, want 0", got)
+	}
+}
+
+func TestParallelQuery_Panic(t *testing.T) {
+	q := Create(1000, func(i int) int { return i })
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want boom", r)
+		}
+	}()
+	q.Parallel(4).Each(func(e int) int {
+		if e == 500 {
+			panic("boom")
+		}
+		return e
+	})
+	t.Errorf("Each() did not panic")
+}
+
+func TestParallelQuery_Index(t *testing.T) {
+	q := Create(100, func(i int) int { return i })
+	ix := NewHashIndex(q, isEven)
+	defer ix.Close()
+	q.Parallel(4).Where(func(e int) bool {
+		return e < 10
+	})
+	if got := ix.Count(true); got != 5 {
+		t.Errorf("HashIndex.Count() after Where() = %d, want 5", got)
+	}
+}
@@ -0,0 +1,506 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A Statement is a parsed query of the SliceQL language.
+//
+// The language is a small dialect of SQL without a FROM clause, since a
+// query always runs against a single Query value:
+//
+//	[SELECT * | column [AS alias], ...]
+//	[WHERE condition]
+//	[GROUP BY expr, ... [HAVING condition]]
+//	[ORDER BY expr [ASC | DESC], ...]
+//	[LIMIT n] [OFFSET n]
+//
+// Every clause is optional. ORDER BY may refer to the aliases of
+// the SELECT clause. Identifiers name struct fields and may be
+// dotted paths into nested structs, such as Address.City. A field is
+// named by its sliceql tag, its json tag or its Go name, in that order,
+// and the fields of embedded structs are promoted. Keywords are
+// case-insensitive. Literals are numbers, single-quoted strings with
+// doubled quotes as escapes, TRUE, FALSE and NULL.
+//
+// Conditions combine comparisons (=, !=, <>, <, <=, >, >=), [NOT] IN
+// (list), [NOT] LIKE pattern, [NOT] BETWEEN low AND high with NOT, AND
+// and OR. In LIKE patterns, % matches any sequence of characters and _
+// matches a single character.
+//
+// The aggregate functions COUNT(*), COUNT(x), SUM(x), AVG(x), MIN(x)
+// and MAX(x) reduce the elements of a group to a single value. They may
+// be used in the SELECT, HAVING and ORDER BY clauses. A query that uses
+// them, or has a GROUP BY or HAVING clause, is grouped: it produces one
+// row per group rather than per element, and without GROUP BY all
+// elements form a single group. As in SQL, the aggregates other than
+// COUNT skip NULL values and are NULL for a group without values.
+type Statement struct {
+	columns []column
+	where   expr
+	groupBy []expr
+	having  expr
+	orderBy []orderTerm
+	limit   int
+	offset  int
+}
+
+// column is a projected column of a SELECT clause.
+type column struct {
+	x     expr
+	alias string
+}
+
+// name returns the name of the column in a result row.
+func (c column) name() string {
+	if c.alias != "" {
+		return c.alias
+	}
+	return c.x.String()
+}
+
+// orderTerm is a single key of an ORDER BY clause.
+type orderTerm struct {
+	x    expr
+	desc bool
+}
+
+// Parse parses the text of a query.
+//
+// Errors are returned as *ParseError values that point at the offending
+// column of src. Field names are not resolved by Parse; use Compile to
+// check them against an element type.
+func Parse(src string) (*Statement, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	return p.statement()
+}
+
+// String returns the query in a normalized form.
+func (s *Statement) String() string {
+	var parts []string
+	if len(s.columns) > 0 {
+		cols := make([]string, len(s.columns))
+		for i, c := range s.columns {
+			cols[i] = c.x.String()
+			if c.alias != "" {
+				cols[i] += " AS " + c.alias
+			}
+		}
+		parts = append(parts, "SELECT "+strings.Join(cols, ", "))
+	}
+	if s.where != nil {
+		parts = append(parts, "WHERE "+s.where.String())
+	}
+	if len(s.groupBy) > 0 {
+		keys := make([]string, len(s.groupBy))
+		for i, x := range s.groupBy {
+			keys[i] = x.String()
+		}
+		parts = append(parts, "GROUP BY "+strings.Join(keys, ", "))
+	}
+	if s.having != nil {
+		parts = append(parts, "HAVING "+s.having.String())
+	}
+	if len(s.orderBy) > 0 {
+		terms := make([]string, len(s.orderBy))
+		for i, t := range s.orderBy {
+			terms[i] = t.x.String()
+			if t.desc {
+				terms[i] += " DESC"
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(terms, ", "))
+	}
+	if s.limit >= 0 {
+		parts = append(parts, "LIMIT "+strconv.Itoa(s.limit))
+	}
+	if s.offset > 0 {
+		parts = append(parts, "OFFSET "+strconv.Itoa(s.offset))
+	}
+	return strings.Join(parts, " ")
+}
+
+// parser is a recursive descent parser over the tokens of a query.
+type parser struct {
+	tokens []token
+	i      int
+}
+
+// peek returns the current token.
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+// next returns the current token and advances to the next one.
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword advances past the current token if it is one of the keywords
+// kw and reports whether it did.
+func (p *parser) keyword(kw ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokKeyword {
+		return "", false
+	}
+	for _, k := range kw {
+		if t.text == k {
+			p.i++
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// expect advances past the current token if it is of kind k with the
+// given text, and returns an error otherwise.
+func (p *parser) expect(k tokenKind, text string) error {
+	t := p.peek()
+	if t.kind != k || t.text != text {
+		return errorAt(t.pos, "expected %q, found %v", text, t)
+	}
+	p.i++
+	return nil
+}
+
+// statement parses a complete query.
+func (p *parser) statement() (*Statement, error) {
+	s := &Statement{limit: -1}
+	var err error
+	if _, ok := p.keyword("SELECT"); ok {
+		if s.columns, err = p.columns(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keyword("WHERE"); ok {
+		if s.where, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keyword("GROUP"); ok {
+		if err = p.expect(tokKeyword, "BY"); err != nil {
+			return nil, err
+		}
+		for {
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			s.groupBy = append(s.groupBy, x)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if _, ok := p.keyword("HAVING"); ok {
+		if s.having, err = p.expr(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keyword("ORDER"); ok {
+		if err = p.expect(tokKeyword, "BY"); err != nil {
+			return nil, err
+		}
+		if s.orderBy, err = p.orderTerms(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keyword("LIMIT"); ok {
+		if s.limit, err = p.count(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keyword("OFFSET"); ok {
+		if s.offset, err = p.count(); err != nil {
+			return nil, err
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorAt(t.pos, "unexpected %v", t)
+	}
+	return s, nil
+}
+
+// columns parses the column list of a SELECT clause.
+// A single * selects every field and yields no columns.
+func (p *parser) columns() ([]column, error) {
+	if p.peek().kind == tokStar {
+		p.next()
+		return nil, nil
+	}
+	var cols []column
+	for {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		c := column{x: x}
+		if _, ok := p.keyword("AS"); ok {
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, errorAt(t.pos, "expected column alias, found %v", t)
+			}
+			c.alias = t.text
+		}
+		cols = append(cols, c)
+		if p.peek().kind != tokComma {
+			return cols, nil
+		}
+		p.next()
+	}
+}
+
+// orderTerms parses the keys of an ORDER BY clause.
+func (p *parser) orderTerms() ([]orderTerm, error) {
+	var terms []orderTerm
+	for {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		kw, _ := p.keyword("ASC", "DESC")
+		terms = append(terms, orderTerm{x: x, desc: kw == "DESC"})
+		if p.peek().kind != tokComma {
+			return terms, nil
+		}
+		p.next()
+	}
+}
+
+// count parses the non-negative integer of a LIMIT or OFFSET clause.
+func (p *parser) count() (int, error) {
+	t := p.next()
+	if t.kind != tokNumber {
+		return 0, errorAt(t.pos, "expected count, found %v", t)
+	}
+	n, err := strconv.Atoi(t.text)
+	if err != nil || n < 0 {
+		return 0, errorAt(t.pos, "invalid count %q", t.text)
+	}
+	return n, nil
+}
+
+// expr parses a condition or value expression.
+func (p *parser) expr() (expr, error) {
+	return p.or()
+}
+
+// or parses a disjunction.
+func (p *parser) or() (expr, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if _, ok := p.keyword("OR"); !ok {
+			return x, nil
+		}
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		x = &logicalExpr{p: t.pos, op: "OR", x: x, y: y}
+	}
+}
+
+// and parses a conjunction.
+func (p *parser) and() (expr, error) {
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if _, ok := p.keyword("AND"); !ok {
+			return x, nil
+		}
+		y, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		x = &logicalExpr{p: t.pos, op: "AND", x: x, y: y}
+	}
+}
+
+// not parses an optionally negated comparison.
+func (p *parser) not() (expr, error) {
+	t := p.peek()
+	if _, ok := p.keyword("NOT"); ok {
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{p: t.pos, x: x}, nil
+	}
+	return p.comparison()
+}
+
+// comparison parses a comparison, IN, LIKE or BETWEEN expression,
+// or a single operand.
+func (p *parser) comparison() (expr, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokOp && t.text != "-" {
+		p.next()
+		y, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "<>" {
+			op = "!="
+		}
+		return &compareExpr{p: t.pos, op: op, x: x, y: y}, nil
+	}
+	_, not := p.keyword("NOT")
+	switch kw, _ := p.keyword("IN", "LIKE", "BETWEEN"); kw {
+	case "IN":
+		list, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		return &inExpr{p: t.pos, x: x, list: list, not: not}, nil
+	case "LIKE":
+		pt := p.next()
+		if pt.kind != tokString {
+			return nil, errorAt(pt.pos, "expected pattern string, found %v", pt)
+		}
+		return newLikeExpr(t.pos, x, pt.text, not), nil
+	case "BETWEEN":
+		lo, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokKeyword, "AND"); err != nil {
+			return nil, err
+		}
+		hi, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenExpr{p: t.pos, x: x, lo: lo, hi: hi, not: not}, nil
+	}
+	if not {
+		nt := p.peek()
+		return nil, errorAt(nt.pos, "expected IN, LIKE or BETWEEN after NOT, found %v", nt)
+	}
+	return x, nil
+}
+
+// list parses a parenthesized, comma-separated list of operands.
+func (p *parser) list() ([]expr, error) {
+	if err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	var list []expr
+	for {
+		x, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, x)
+		t := p.next()
+		if t.kind == tokRParen {
+			return list, nil
+		}
+		if t.kind != tokComma {
+			return nil, errorAt(t.pos, "expected \",\" or \")\", found %v", t)
+		}
+	}
+}
+
+// primary parses a field, a literal or a parenthesized expression.
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokIdent:
+		if p.peek().kind == tokLParen {
+			return p.call(t)
+		}
+		return &identExpr{p: t.pos, name: t.text}, nil
+	case tokNumber:
+		return number(t, false)
+	case tokString:
+		return &literalExpr{p: t.pos, value: t.text}, nil
+	case tokKeyword:
+		switch t.text {
+		case "TRUE", "FALSE":
+			return &literalExpr{p: t.pos, value: t.text == "TRUE"}, nil
+		case "NULL":
+			return &literalExpr{p: t.pos, value: nil}, nil
+		}
+	case tokOp:
+		if t.text == "-" && p.peek().kind == tokNumber {
+			return number(p.next(), true)
+		}
+	case tokLParen:
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, errorAt(t.pos, "unexpected %v", t)
+}
+
+// call parses the argument of the aggregate function named by t.
+func (p *parser) call(t token) (expr, error) {
+	fn := strings.ToUpper(t.text)
+	if !aggregates[fn] {
+		return nil, errorAt(t.pos, "unknown function %q", t.text)
+	}
+	p.next()
+	x := &callExpr{p: t.pos, fn: fn}
+	if fn == "COUNT" && p.peek().kind == tokStar {
+		p.next()
+	} else {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		x.arg = arg
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// number converts a number token into a literal, negated if neg is true.
+func number(t token, neg bool) (expr, error) {
+	text := t.text
+	if neg {
+		text = "-" + text
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &literalExpr{p: t.pos, value: n}, nil
+		}
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, errorAt(t.pos, "invalid number %q", t.text)
+	}
+	return &literalExpr{p: t.pos, value: f}, nil
+}
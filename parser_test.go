@@ -0,0 +1,174 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "empty query",
+			src:  "",
+			want: "",
+		},
+		{
+			name: "where only",
+			src:  "where Age > 30",
+			want: "WHERE Age > 30",
+		},
+		{
+			name: "all clauses",
+			src:  "SELECT Name, Age AS years WHERE Age >= 18 ORDER BY Name DESC, Age LIMIT 10 OFFSET 5",
+			want: "SELECT Name, Age AS years WHERE Age >= 18 ORDER BY Name DESC, Age LIMIT 10 OFFSET 5",
+		},
+		{
+			name: "select star",
+			src:  "SELECT * LIMIT 0",
+			want: "LIMIT 0",
+		},
+		{
+			name: "precedence",
+			src:  "WHERE a = 1 OR b = 2 AND NOT c = 3",
+			want: "WHERE a = 1 OR (b = 2 AND NOT c = 3)",
+		},
+		{
+			name: "parentheses",
+			src:  "WHERE (a = 1 OR b = 2) AND c <> 3",
+			want: "WHERE (a = 1 OR b = 2) AND c != 3",
+		},
+		{
+			name: "in, like and between",
+			src:  "WHERE Name NOT IN ('Bob', 'O''Neil') AND Name LIKE 'J%' AND Age NOT BETWEEN -1 AND 2.5",
+			want: "WHERE Name NOT IN ('Bob', 'O''Neil') AND Name LIKE 'J%' AND Age NOT BETWEEN -1 AND 2.5",
+		},
+		{
+			name: "literals",
+			src:  "WHERE a = TRUE OR b = false OR c = null OR d = 1e3 OR e = .5",
+			want: "WHERE a = TRUE OR b = FALSE OR c = NULL OR d = 1000.0 OR e = 0.5",
+		},
+		{
+			name: "field path",
+			src:  "WHERE Address.City = 'Berlin'",
+			want: "WHERE Address.City = 'Berlin'",
+		},
+		{
+			name: "group by and having",
+			src:  "select Dept, count(*) as n, avg(Age) group by Dept having Count(*) > 1 order by n desc",
+			want: "SELECT Dept, COUNT(*) AS n, AVG(Age) GROUP BY Dept HAVING COUNT(*) > 1 ORDER BY n DESC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+			// The normalized form must parse to itself.
+			s, err = Parse(tt.want)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.want, err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_Parse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		column int
+	}{
+		{
+			name:   "unexpected character",
+			src:    "WHERE Age ? 3",
+			column: 11,
+		},
+		{
+			name:   "unterminated string",
+			src:    "WHERE Name = 'Bob",
+			column: 14,
+		},
+		{
+			name:   "missing operand",
+			src:    "WHERE Age >",
+			column: 12,
+		},
+		{
+			name:   "missing by",
+			src:    "ORDER Name",
+			column: 7,
+		},
+		{
+			name:   "negative limit",
+			src:    "LIMIT -1",
+			column: 7,
+		},
+		{
+			name:   "trailing tokens",
+			src:    "WHERE Age > 3 Name",
+			column: 15,
+		},
+		{
+			name:   "clause order",
+			src:    "LIMIT 3 WHERE Age > 3",
+			column: 9,
+		},
+		{
+			name:   "dangling not",
+			src:    "WHERE Age NOT 3",
+			column: 15,
+		},
+		{
+			name:   "malformed path",
+			src:    "WHERE Address..City = 1",
+			column: 7,
+		},
+		{
+			name:   "unclosed list",
+			src:    "WHERE Age IN (1, 2",
+			column: 19,
+		},
+		{
+			name:   "unknown function",
+			src:    "SELECT LEN(Name)",
+			column: 8,
+		},
+		{
+			name:   "missing group by",
+			src:    "GROUP Name",
+			column: 7,
+		},
+		{
+			name:   "unclosed call",
+			src:    "SELECT COUNT(*",
+			column: 15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Parse() error = %v, want *ParseError", err)
+			}
+			if perr.Column != tt.column {
+				t.Errorf("Parse() error = %v, want column %d", err, tt.column)
+			}
+		})
+	}
+}
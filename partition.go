@@ -0,0 +1,129 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import "slices"
+
+// Chunk splits the elements of the Query into consecutive batches of n
+// elements, such as for bulk inserts. The last batch holds the
+// remaining elements and may be shorter.
+//
+// The batches are subslices of the Query that share its elements, with
+// their capacity clipped so that appending to one batch does not
+// overwrite the next. The source Query is not modified. An n less than
+// 1 yields an empty Query.
+func Chunk[E any](q *Query[E], n int) *Query[[]E] {
+	if n < 1 {
+		return &Query[[]E]{}
+	}
+	v := *q
+	// Neither the number of batches nor their bounds may overflow for
+	// an n close to math.MaxInt.
+	batches := len(v) / n
+	if len(v)%n != 0 {
+		batches++
+	}
+	result := Query[[]E](make([][]E, 0, batches))
+	for i := 0; i < len(v); i += n {
+		result = append(result, slices.Clip(v[i:i+min(n, len(v)-i)]))
+	}
+	return &result
+}
+
+// Window returns the sliding windows of size elements of the Query,
+// starting every step elements, such as for moving averages. Every
+// window holds exactly size elements, so a Query shorter than size
+// yields no windows, and elements after the last full window are left
+// out.
+//
+// Like the batches of Chunk, the windows are clipped subslices of the
+// Query. The source Query is not modified. A size or step less than 1
+// yields an empty Query.
+func Window[E any](q *Query[E], size, step int) *Query[[]E] {
+	if size < 1 || step < 1 || size > len(*q) {
+		return &Query[[]E]{}
+	}
+	v := *q
+	result := Query[[]E](make([][]E, 0, (len(v)-size)/step+1))
+	for i := 0; i <= len(v)-size; i += step {
+		result = append(result, slices.Clip(v[i:i+size]))
+		if step > len(v)-size-i {
+			break
+		}
+	}
+	return &result
+}
+
+// Partition splits the elements of the Query in a single pass into
+// those that satisfy f and those that do not, keeping their order.
+//
+// The function returns two new Queries. The source Query is not
+// modified. As with Where, a nil f is satisfied by no element.
+func (q *Query[E]) Partition(f func(E) bool) (matched, unmatched *Query[E]) {
+	in, out := Query[E](make([]E, 0)), Query[E](make([]E, 0))
+	for _, e := range *q {
+		if f != nil && f(e) {
+			in = append(in, e)
+		} else {
+			out = append(out, e)
+		}
+	}
+	return &in, &out
+}
+
+// SkipWhile removes the leading elements of the Query that satisfy f,
+// up to the first element that does not.
+//
+// Returns: a pointer to the modified Query.
+//
+// A nil f is satisfied by no element, so nothing is skipped.
+func (q *Query[E]) SkipWhile(f func(E) bool) *Query[E] {
+	return q.Skip(q.prefix(f))
+}
+
+// TakeWhile keeps the leading elements of the Query that satisfy f,
+// up to the first element that does not.
+//
+// Returns: a pointer to the modified Query.
+//
+// A nil f is satisfied by no element, so the Query is emptied.
+func (q *Query[E]) TakeWhile(f func(E) bool) *Query[E] {
+	return q.Take(q.prefix(f))
+}
+
+// SkipLast removes the last n elements from the Query.
+//
+// Returns: a pointer to the modified Query.
+//
+// Like Skip, the count is clamped to the bounds of the Query: a negative
+// n removes nothing and an n beyond the number of elements empties the
+// Query.
+func (q *Query[E]) SkipLast(n int) *Query[E] {
+	return q.Take(len(*q) - min(max(n, 0), len(*q)))
+}
+
+// TakeLast keeps the last n elements of the Query.
+//
+// Returns: a pointer to the modified Query.
+//
+// Like Take, the count is clamped to the bounds of the Query: a negative
+// n empties the Query and an n beyond the number of elements keeps all
+// of them.
+func (q *Query[E]) TakeLast(n int) *Query[E] {
+	return q.Skip(len(*q) - min(max(n, 0), len(*q)))
+}
+
+// prefix returns the number of leading elements that satisfy f.
+func (q *Query[E]) prefix(f func(E) bool) int {
+	if f == nil {
+		return 0
+	}
+	for i, e := range *q {
+		if !f(e) {
+			return i
+		}
+	}
+	return len(*q)
+}
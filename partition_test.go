@@ -0,0 +1,329 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_Chunk(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query[int]
+		n    int
+		want [][]int
+	}{
+		{
+			name: "zero size",
+			q:    &Query[int]{1, 2, 3},
+			n:    0,
+			want: [][]int{},
+		},
+		{
+			name: "negative size",
+			q:    &Query[int]{1, 2, 3},
+			n:    -2,
+			want: [][]int{},
+		},
+		{
+			name: "empty slice",
+			q:    &Query[int]{},
+			n:    2,
+			want: [][]int{},
+		},
+		{
+			name: "even split",
+			q:    &Query[int]{1, 2, 3, 4},
+			n:    2,
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "short last chunk",
+			q:    &Query[int]{1, 2, 3, 4, 5},
+			n:    2,
+			want: [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		{
+			name: "size beyond length",
+			q:    &Query[int]{1, 2, 3},
+			n:    10,
+			want: [][]int{{1, 2, 3}},
+		},
+		{
+			name: "max size",
+			q:    &Query[int]{1, 2, 3},
+			n:    math.MaxInt,
+			want: [][]int{{1, 2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Chunk(tt.q, tt.n); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Chunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Chunk_Clipped(t *testing.T) {
+	q := &Query[int]{1, 2, 3, 4}
+	chunks := Chunk(q, 2)
+	_ = append((*chunks)[0], 9)
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(q.ToSlice(), want) {
+		t.Errorf("append to chunk changed the Query to %v, want %v", q, want)
+	}
+}
+
+func Test_Window(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          *Query[int]
+		size, step int
+		want       [][]int
+	}{
+		{
+			name: "zero size",
+			q:    &Query[int]{1, 2, 3},
+			size: 0,
+			step: 1,
+			want: [][]int{},
+		},
+		{
+			name: "zero step",
+			q:    &Query[int]{1, 2, 3},
+			size: 2,
+			step: 0,
+			want: [][]int{},
+		},
+		{
+			name: "size beyond length",
+			q:    &Query[int]{1, 2, 3},
+			size: 4,
+			step: 1,
+			want: [][]int{},
+		},
+		{
+			name: "sliding",
+			q:    &Query[int]{1, 2, 3, 4},
+			size: 2,
+			step: 1,
+			want: [][]int{{1, 2}, {2, 3}, {3, 4}},
+		},
+		{
+			name: "tumbling",
+			q:    &Query[int]{1, 2, 3, 4, 5},
+			size: 2,
+			step: 2,
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "hopping",
+			q:    &Query[int]{1, 2, 3, 4, 5, 6, 7},
+			size: 2,
+			step: 3,
+			want: [][]int{{1, 2}, {4, 5}},
+		},
+		{
+			name: "whole slice",
+			q:    &Query[int]{1, 2, 3},
+			size: 3,
+			step: 5,
+			want: [][]int{{1, 2, 3}},
+		},
+		{
+			name: "max step",
+			q:    &Query[int]{1, 2, 3},
+			size: 1,
+			step: math.MaxInt,
+			want: [][]int{{1}},
+		},
+		{
+			name: "max size",
+			q:    &Query[int]{1, 2, 3},
+			size: math.MaxInt,
+			step: 1,
+			want: [][]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Window(tt.q, tt.size, tt.step); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Window() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_Partition(t *testing.T) {
+	tests := []struct {
+		name          string
+		q             *Query[int]
+		f             func(int) bool
+		wantMatched   []int
+		wantUnmatched []int
+	}{
+		{
+			name:          "nil test",
+			q:             &Query[int]{1, 2, 3},
+			wantMatched:   []int{},
+			wantUnmatched: []int{1, 2, 3},
+		},
+		{
+			name:          "empty slice",
+			q:             &Query[int]{},
+			f:             isEven,
+			wantMatched:   []int{},
+			wantUnmatched: []int{},
+		},
+		{
+			name:          "non-empty slice",
+			q:             &Query[int]{1, 2, 3, 4, 5},
+			f:             isEven,
+			wantMatched:   []int{2, 4},
+			wantUnmatched: []int{1, 3, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := tt.q.Clone().ToSlice()
+			matched, unmatched := tt.q.Partition(tt.f)
+			if !reflect.DeepEqual(matched.ToSlice(), tt.wantMatched) {
+				t.Errorf("Query.Partition() matched = %v, want %v", matched, tt.wantMatched)
+			}
+			if !reflect.DeepEqual(unmatched.ToSlice(), tt.wantUnmatched) {
+				t.Errorf("Query.Partition() unmatched = %v, want %v", unmatched, tt.wantUnmatched)
+			}
+			if !reflect.DeepEqual(tt.q.ToSlice(), src) {
+				t.Errorf("Query.Partition() modified the Query to %v, want %v", tt.q, src)
+			}
+		})
+	}
+}
+
+func TestQuery_SkipTakeWhile(t *testing.T) {
+	less3 := func(e int) bool {
+		return e < 3
+	}
+	tests := []struct {
+		name          string
+		q             *Query[int]
+		f             func(int) bool
+		wantSkipWhile []int
+		wantTakeWhile []int
+	}{
+		{
+			name:          "nil test",
+			q:             &Query[int]{1, 2, 3},
+			wantSkipWhile: []int{1, 2, 3},
+			wantTakeWhile: []int{},
+		},
+		{
+			name:          "empty slice",
+			q:             &Query[int]{},
+			f:             less3,
+			wantSkipWhile: []int{},
+			wantTakeWhile: []int{},
+		},
+		{
+			name:          "prefix",
+			q:             &Query[int]{1, 2, 3, 1, 4},
+			f:             less3,
+			wantSkipWhile: []int{3, 1, 4},
+			wantTakeWhile: []int{1, 2},
+		},
+		{
+			name:          "every element",
+			q:             &Query[int]{1, 2, 1},
+			f:             less3,
+			wantSkipWhile: []int{},
+			wantTakeWhile: []int{1, 2, 1},
+		},
+		{
+			name:          "no element",
+			q:             &Query[int]{3, 1},
+			f:             less3,
+			wantSkipWhile: []int{3, 1},
+			wantTakeWhile: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Clone().SkipWhile(tt.f); !reflect.DeepEqual(got.ToSlice(), tt.wantSkipWhile) {
+				t.Errorf("Query.SkipWhile() = %v, want %v", got, tt.wantSkipWhile)
+			}
+			if got := tt.q.Clone().TakeWhile(tt.f); !reflect.DeepEqual(got.ToSlice(), tt.wantTakeWhile) {
+				t.Errorf("Query.TakeWhile() = %v, want %v", got, tt.wantTakeWhile)
+			}
+		})
+	}
+}
+
+func TestQuery_SkipTakeLast(t *testing.T) {
+	tests := []struct {
+		name         string
+		q            *Query[int]
+		n            int
+		wantSkipLast []int
+		wantTakeLast []int
+	}{
+		{
+			name:         "negative count",
+			q:            &Query[int]{1, 2, 3},
+			n:            -1,
+			wantSkipLast: []int{1, 2, 3},
+			wantTakeLast: []int{},
+		},
+		{
+			name:         "zero count",
+			q:            &Query[int]{1, 2, 3},
+			n:            0,
+			wantSkipLast: []int{1, 2, 3},
+			wantTakeLast: []int{},
+		},
+		{
+			name:         "within bounds",
+			q:            &Query[int]{1, 2, 3, 4},
+			n:            1,
+			wantSkipLast: []int{1, 2, 3},
+			wantTakeLast: []int{4},
+		},
+		{
+			name:         "beyond length",
+			q:            &Query[int]{1, 2, 3},
+			n:            10,
+			wantSkipLast: []int{},
+			wantTakeLast: []int{1, 2, 3},
+		},
+		{
+			name:         "empty slice",
+			q:            &Query[int]{},
+			n:            2,
+			wantSkipLast: []int{},
+			wantTakeLast: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Clone().SkipLast(tt.n); !reflect.DeepEqual(got.ToSlice(), tt.wantSkipLast) {
+				t.Errorf("Query.SkipLast() = %v, want %v", got, tt.wantSkipLast)
+			}
+			if got := tt.q.Clone().TakeLast(tt.n); !reflect.DeepEqual(got.ToSlice(), tt.wantTakeLast) {
+				t.Errorf("Query.TakeLast() = %v, want %v", got, tt.wantTakeLast)
+			}
+		})
+	}
+}
+
+func TestQuery_TakeWhile_Index(t *testing.T) {
+	q := &Query[int]{2, 4, 5, 6}
+	ix := NewHashIndex(q, isEven)
+	defer ix.Close()
+	q.TakeWhile(isEven)
+	if got := ix.Count(true); got != 2 {
+		t.Errorf("HashIndex.Count() after TakeWhile() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// A Plan describes the stages a Lazy pipeline runs, starting with its
+// source. A Plan of a profiled pipeline also holds the statistics of
+// its last run.
+type Plan struct {
+	// Stages are the stages of the pipeline, starting with its source.
+	Stages []PlanStage
+	// Run holds the statistics of the last profiled run as a whole,
+	// or nil if the pipeline has not been profiled.
+	Run *Stats
+}
+
+// A PlanStage is a single stage of a Plan.
+type PlanStage struct {
+	// Op is the operation of the stage, such as Where or Take,
+	// or Slice for the source of the pipeline.
+	Op string
+	// Arg is the argument of the operation, such as the count of a
+	// Take or the length of a Slice, or the empty string.
+	Arg string
+	// Stats holds the statistics of the stage in the last profiled run,
+	// or nil if the pipeline has not been profiled.
+	Stats *Stats
+}
+
+// Stats are the statistics of a profiled run of a pipeline or of one of
+// its stages.
+//
+// In and Out count the elements pulled into and yielded by a stage.
+// Elapsed is the time spent in a stage itself, excluding the time spent
+// in the stages before and after it. The heap allocations Allocs and
+// Bytes cannot be attributed to single stages and are only measured for
+// the run as a whole.
+type Stats struct {
+	In, Out       int
+	Elapsed       time.Duration
+	Allocs, Bytes uint64
+}
+
+// String returns the plan as a tree, with the last stage at its root
+// and the source at its leaf, followed by the statistics of the run
+// if the pipeline has been profiled:
+//
+//	Take(2) in=4 out=2 time=1.1µs
+//	└─ Where in=4 out=4 time=2.3µs
+//	   └─ Slice(8) out=4 time=710ns
+//	run: time=5.4µs allocs=1 bytes=16
+func (p *Plan) String() string {
+	var b strings.Builder
+	for i := len(p.Stages) - 1; i >= 0; i-- {
+		if depth := len(p.Stages) - 1 - i; depth > 0 {
+			b.WriteString(strings.Repeat("   ", depth-1))
+			b.WriteString("└─ ")
+		}
+		st := p.Stages[i]
+		b.WriteString(st.Op)
+		if st.Arg != "" {
+			b.WriteString("(" + st.Arg + ")")
+		}
+		if s := st.Stats; s != nil {
+			if i > 0 {
+				fmt.Fprintf(&b, " in=%d", s.In)
+			}
+			fmt.Fprintf(&b, " out=%d time=%v", s.Out, s.Elapsed)
+		}
+		b.WriteByte('\n')
+	}
+	if s := p.Run; s != nil {
+		fmt.Fprintf(&b, "run: time=%v allocs=%d bytes=%d\n", s.Elapsed, s.Allocs, s.Bytes)
+	}
+	return b.String()
+}
+
+// Plan returns the plan of the pipeline, with its stages rewritten as
+// described for Lazy. For a profiled pipeline it holds the statistics
+// and the stages of the last run, if any, including the stage that
+// Count or Any add for their test.
+func (l *Lazy[E]) Plan() *Plan {
+	if l.prof != nil {
+		if p := l.prof.last.Load(); p != nil {
+			return p
+		}
+	}
+	stages := optimize(l.stages)
+	p := &Plan{Stages: make([]PlanStage, 0, len(stages)+1)}
+	p.Stages = append(p.Stages, PlanStage{Op: "Slice", Arg: strconv.Itoa(len(l.src))})
+	for _, st := range stages {
+		p.Stages = append(p.Stages, st.plan())
+	}
+	return p
+}
+
+// Explain returns the plan of the pipeline as a human-readable tree,
+// as described for Plan.String.
+//
+// To see how many elements every stage processed and where the time
+// went, call Explain on a profiled pipeline after running it:
+//
+//	l := q.Lazy().Profile().Where(f).Sort(less).Take(10)
+//	top := l.ToSlice()
+//	fmt.Print(l.Explain())
+func (l *Lazy[E]) Explain() string {
+	return l.Plan().String()
+}
+
+// Profile returns a copy of the pipeline that records statistics
+// whenever a terminal operation runs it. Profiling is kept by stages
+// added to the returned Lazy, and every pipeline keeps the statistics
+// of its own last run, see Plan.
+//
+// Profiling slows a pipeline down, since it reads the clock whenever
+// an element passes from one stage to the next.
+func (l *Lazy[E]) Profile() *Lazy[E] {
+	return &Lazy[E]{src: l.src, stages: l.stages, prof: &profile{}}
+}
+
+// profile holds the plan of the last profiled run of a pipeline.
+type profile struct {
+	last atomic.Pointer[Plan]
+}
+
+// plan returns the description of the stage.
+func (st stage[E]) plan() PlanStage {
+	ps := PlanStage{Op: st.kind.String()}
+	switch st.kind {
+	case skipStage, takeStage, topKStage:
+		ps.Arg = strconv.Itoa(st.n)
+	}
+	return ps
+}
+
+// String returns the name of the operation of the stage kind.
+func (k stageKind) String() string {
+	switch k {
+	case whereStage:
+		return "Where"
+	case eachStage:
+		return "Each"
+	case skipStage:
+		return "Skip"
+	case takeStage:
+		return "Take"
+	case sortStage:
+		return "Sort"
+	case reverseStage:
+		return "Reverse"
+	case topKStage:
+		return "TopK"
+	}
+	return "stage(" + strconv.Itoa(int(k)) + ")"
+}
+
+// profiler charges the time of a profiled run to the stages of the
+// pipeline. The source is stage 0, the recorded stages follow, and the
+// consumer of the run comes last.
+type profiler struct {
+	stats []Stats
+	cur   int
+	last  time.Time
+}
+
+// switchTo charges the time since the last switch to the current stage
+// and makes stage i the current one.
+func (p *profiler) switchTo(i int) {
+	now := time.Now()
+	p.stats[p.cur].Elapsed += now.Sub(p.last)
+	p.cur, p.last = i, now
+}
+
+// profiled runs the stages over the source of the pipeline and records
+// their plan with the statistics of the run.
+func (l *Lazy[E]) profiled(stages []stage[E], yield func(E) bool) {
+	n := len(stages)
+	p := &profiler{stats: make([]Stats, n+2), cur: n + 1}
+	p.stats[0].In = len(l.src)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	p.last = start
+	s := profileSeq(p, 0, slices.Values(l.src))
+	for i, st := range stages {
+		s = profileSeq(p, i+1, st.apply(s))
+	}
+	s(yield)
+	p.switchTo(n + 1)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	plan := &Plan{
+		Stages: make([]PlanStage, 0, n+1),
+		Run: &Stats{
+			In:      len(l.src),
+			Out:     p.stats[n].Out,
+			Elapsed: elapsed,
+			Allocs:  after.Mallocs - before.Mallocs,
+			Bytes:   after.TotalAlloc - before.TotalAlloc,
+		},
+	}
+	plan.Stages = append(plan.Stages, PlanStage{Op: "Slice", Arg: strconv.Itoa(len(l.src)), Stats: &p.stats[0]})
+	for i, st := range stages {
+		ps := st.plan()
+		ps.Stats = &p.stats[i+1]
+		plan.Stages = append(plan.Stages, ps)
+	}
+	l.prof.last.Store(plan)
+}
+
+// profileSeq wraps the output s of stage i of a profiled run, counting
+// the elements passed on to the next stage and switching the stage the
+// time is charged to.
+func profileSeq[E any](p *profiler, i int, s iter.Seq[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		p.switchTo(i)
+		s(func(e E) bool {
+			p.stats[i].Out++
+			p.stats[i+1].In++
+			p.switchTo(i + 1)
+			ok := yield(e)
+			p.switchTo(i)
+			return ok
+		})
+		p.switchTo(i + 1)
+	}
+}
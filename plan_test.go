@@ -0,0 +1,107 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLazy_Explain(t *testing.T) {
+	l := NewLazy([]int{5, 3, 8, 1}).Where(isEven).Each(func(e int) int {
+		return e * 2
+	}).Sort(func(a, b int) bool {
+		return a < b
+	}).Reverse().Skip(1).Take(2)
+	want := `Take(2)
+└─ Skip(1)
+   └─ Reverse
+      └─ Sort
+         └─ Each
+            └─ Where
+               └─ Slice(4)
+`
+	if got := l.Explain(); got != want {
+		t.Errorf("Lazy.Explain() = \n%s, want \n%s", got, want)
+	}
+	if p := l.Plan(); p.Run != nil || p.Stages[0].Stats != nil {
+		t.Errorf("Lazy.Plan() of unprofiled pipeline has statistics")
+	}
+}
+
+func TestLazy_Profile(t *testing.T) {
+	base := NewLazy([]int{1, 2, 3, 4, 5, 6, 7, 8}).Profile()
+	l := base.Where(isEven).Take(2)
+	if p := l.Plan(); p.Run != nil {
+		t.Errorf("Lazy.Plan() before run has statistics")
+	}
+	if got := l.ToSlice(); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Fatalf("Lazy.ToSlice() = %v, want %v", got, []int{2, 4})
+	}
+	p := l.Plan()
+	type count struct {
+		op      string
+		in, out int
+	}
+	var got []count
+	for _, st := range p.Stages {
+		got = append(got, count{st.Op, st.Stats.In, st.Stats.Out})
+	}
+	want := []count{
+		{"Slice", 8, 4},
+		{"Where", 4, 2},
+		{"Take", 2, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lazy.Plan() stages = %v, want %v", got, want)
+	}
+	if p.Run == nil || p.Run.In != 8 || p.Run.Out != 2 {
+		t.Errorf("Lazy.Plan().Run = %+v, want in 8 and out 2", p.Run)
+	}
+	var elapsed int64
+	for _, st := range p.Stages {
+		elapsed += int64(st.Stats.Elapsed)
+	}
+	if elapsed > int64(p.Run.Elapsed) {
+		t.Errorf("stages took %d ns, more than the run with %d ns", elapsed, p.Run.Elapsed)
+	}
+	explain := l.Explain()
+	for _, s := range []string{"Take(2) in=2 out=2 time=", "└─ Where in=4 out=2 time=", "└─ Slice(8) out=4 time=", "run: time="} {
+		if !strings.Contains(explain, s) {
+			t.Errorf("Lazy.Explain() = \n%s, want it to contain %q", explain, s)
+		}
+	}
+
+	// Every pipeline keeps its own statistics.
+	if p := base.Plan(); p.Run != nil {
+		t.Errorf("Lazy.Plan() of source pipeline has statistics")
+	}
+	if n := l.Count(nil); n != 2 {
+		t.Errorf("Lazy.Count() = %d, want 2", n)
+	}
+	if p := l.Plan(); p.Stages[2].Stats.Out != 2 {
+		t.Errorf("Lazy.Plan() after second run = %v, want statistics of that run", p)
+	}
+}
+
+func TestLazy_Profile_Buffered(t *testing.T) {
+	l := NewLazy([]int{4, 1, 3, 2}).Profile().Sort(func(a, b int) bool {
+		return a < b
+	})
+	if got := l.First(); got != 1 {
+		t.Fatalf("Lazy.First() = %d, want 1", got)
+	}
+	p := l.Plan()
+	if s := p.Stages[0].Stats; s.Out != 4 {
+		t.Errorf("Slice out = %d, want 4", s.Out)
+	}
+	if s := p.Stages[1].Stats; s.In != 4 || s.Out != 1 {
+		t.Errorf("Sort in = %d, out = %d, want 4 and 1", s.In, s.Out)
+	}
+	if p.Run.Allocs == 0 {
+		t.Errorf("Run.Allocs = 0, want the buffer of Sort")
+	}
+}
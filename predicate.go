@@ -0,0 +1,233 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+)
+
+// A Field describes a field of the element type E with values of the
+// ordered type V. Its methods build predicates on the field that can be
+// printed in the syntax of the query language:
+//
+//	var age = Field[Person, int]{Name: "Age", Get: func(p Person) int { return p.Age }}
+//	q.Where(age.Gt(30).Test)
+type Field[E any, V cmp.Ordered] struct {
+	// Name is the name of the field in the query language.
+	Name string
+	// Get returns the value of the field of an element.
+	Get func(E) V
+}
+
+// Eq returns a predicate that reports whether the field equals v.
+func (f Field[E, V]) Eq(v V) Predicate[E] {
+	return f.compare("=", v, func(x V) bool { return x == v })
+}
+
+// Ne returns a predicate that reports whether the field differs from v.
+func (f Field[E, V]) Ne(v V) Predicate[E] {
+	return f.compare("!=", v, func(x V) bool { return x != v })
+}
+
+// Lt returns a predicate that reports whether the field is less than v.
+func (f Field[E, V]) Lt(v V) Predicate[E] {
+	return f.compare("<", v, func(x V) bool { return cmp.Less(x, v) })
+}
+
+// Le returns a predicate that reports whether the field is less than
+// or equal to v.
+func (f Field[E, V]) Le(v V) Predicate[E] {
+	return f.compare("<=", v, func(x V) bool { return cmp.Compare(x, v) <= 0 })
+}
+
+// Gt returns a predicate that reports whether the field is greater
+// than v.
+func (f Field[E, V]) Gt(v V) Predicate[E] {
+	return f.compare(">", v, func(x V) bool { return cmp.Less(v, x) })
+}
+
+// Ge returns a predicate that reports whether the field is greater
+// than or equal to v.
+func (f Field[E, V]) Ge(v V) Predicate[E] {
+	return f.compare(">=", v, func(x V) bool { return cmp.Compare(x, v) >= 0 })
+}
+
+// In returns a predicate that reports whether the field equals one
+// of the values vs. In without values is false for every element.
+func (f Field[E, V]) In(vs ...V) Predicate[E] {
+	vs = slices.Clone(vs)
+	list := make([]expr, len(vs))
+	for i, v := range vs {
+		list[i] = valueExpr(v)
+	}
+	get := f.Get
+	return Predicate[E]{
+		x: &inExpr{x: f.ident(), list: list},
+		test: func(e E) bool {
+			return slices.Contains(vs, get(e))
+		},
+	}
+}
+
+// Between returns a predicate that reports whether the field lies
+// within the inclusive range from lo to hi.
+func (f Field[E, V]) Between(lo, hi V) Predicate[E] {
+	get := f.Get
+	return Predicate[E]{
+		x: &betweenExpr{x: f.ident(), lo: valueExpr(lo), hi: valueExpr(hi)},
+		test: func(e E) bool {
+			v := get(e)
+			return cmp.Compare(v, lo) >= 0 && cmp.Compare(v, hi) <= 0
+		},
+	}
+}
+
+// Less reports whether the field of a is less than the field of b.
+// It can be passed to Query.Sort.
+func (f Field[E, V]) Less(a, b E) bool {
+	return cmp.Less(f.Get(a), f.Get(b))
+}
+
+// Compare compares the field of a with the field of b like cmp.Compare.
+// It can be passed to OrderByFunc and OrderedQuery.ThenByFunc.
+func (f Field[E, V]) Compare(a, b E) int {
+	return cmp.Compare(f.Get(a), f.Get(b))
+}
+
+// String returns the name of the field.
+func (f Field[E, V]) String() string {
+	return f.Name
+}
+
+// compare returns the predicate of the comparison of the field
+// with v by the operator op.
+func (f Field[E, V]) compare(op string, v V, test func(V) bool) Predicate[E] {
+	get := f.Get
+	return Predicate[E]{
+		x: &compareExpr{op: op, x: f.ident(), y: valueExpr(v)},
+		test: func(e E) bool {
+			return test(get(e))
+		},
+	}
+}
+
+// ident returns the reference to the field in the query language.
+func (f Field[E, V]) ident() expr {
+	return &identExpr{name: f.Name}
+}
+
+// A Predicate is a condition on elements of type E that, unlike a
+// plain func(E) bool, can be printed. Its method value p.Test can be
+// passed to Where, Count, Any and All:
+//
+//	p := And(age.Gt(30), Not(name.Eq("Bob")))
+//	log.Printf("filter: %v", p)
+//	q.Where(p.Test)
+//
+// The zero Predicate is true for every element.
+type Predicate[E any] struct {
+	x    expr
+	test func(E) bool
+}
+
+// And returns a predicate that reports whether every predicate of ps
+// is true, evaluated from left to right. And without predicates is true.
+func And[E any](ps ...Predicate[E]) Predicate[E] {
+	return logical("AND", ps)
+}
+
+// Or returns a predicate that reports whether any predicate of ps is
+// true, evaluated from left to right. Or without predicates is false.
+func Or[E any](ps ...Predicate[E]) Predicate[E] {
+	return logical("OR", ps)
+}
+
+// Not returns a predicate that negates p.
+func Not[E any](p Predicate[E]) Predicate[E] {
+	return Predicate[E]{
+		x: &notExpr{x: p.expr()},
+		test: func(e E) bool {
+			return !p.Test(e)
+		},
+	}
+}
+
+// logical combines the predicates ps with the logical operator op.
+func logical[E any](op string, ps []Predicate[E]) Predicate[E] {
+	and := op == "AND"
+	if len(ps) == 0 {
+		return Predicate[E]{
+			x: &literalExpr{value: and},
+			test: func(E) bool {
+				return and
+			},
+		}
+	}
+	ps = slices.Clone(ps)
+	x := ps[0].expr()
+	for _, p := range ps[1:] {
+		x = &logicalExpr{op: op, x: x, y: p.expr()}
+	}
+	return Predicate[E]{
+		x: x,
+		test: func(e E) bool {
+			for _, p := range ps {
+				if p.Test(e) != and {
+					return !and
+				}
+			}
+			return and
+		},
+	}
+}
+
+// Test reports whether the element e satisfies the predicate.
+func (p Predicate[E]) Test(e E) bool {
+	if p.test == nil {
+		return true
+	}
+	return p.test(e)
+}
+
+// String returns the predicate in the syntax of the conditions of the
+// query language, such as Age > 30 AND Name IN ('Bob', 'Jane').
+func (p Predicate[E]) String() string {
+	return p.expr().String()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The text is the same as the one returned by String.
+func (p Predicate[E]) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// expr returns the condition of the predicate. The condition of the
+// zero Predicate is TRUE.
+func (p Predicate[E]) expr() expr {
+	if p.x == nil {
+		return &literalExpr{value: true}
+	}
+	return p.x
+}
+
+// valueExpr returns the literal of the value v.
+func valueExpr[V cmp.Ordered](v V) expr {
+	var value any
+	switch v := any(v).(type) {
+	case string:
+		value = v
+	case int:
+		value = int64(v)
+	case int64:
+		value = v
+	case float64:
+		value = v
+	default:
+		value = normalize(reflect.ValueOf(v))
+	}
+	return &literalExpr{value: value}
+}
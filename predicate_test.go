@@ -0,0 +1,166 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	memberName  = Field[member, string]{Name: "Name", Get: func(m member) string { return m.Name }}
+	memberAge   = Field[member, int]{Name: "Age", Get: func(m member) int { return m.Age }}
+	memberScore = Field[member, float64]{Name: "Score", Get: func(m member) float64 { return m.Score }}
+)
+
+func TestPredicate(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Predicate[member]
+		str  string
+		want []string
+	}{
+		{
+			name: "zero",
+			p:    Predicate[member]{},
+			str:  "TRUE",
+			want: []string{"Bob", "Jenny", "John", "Michael", "Jane"},
+		},
+		{
+			name: "eq",
+			p:    memberName.Eq("Bob"),
+			str:  "Name = 'Bob'",
+			want: []string{"Bob"},
+		},
+		{
+			name: "ne",
+			p:    memberAge.Ne(42),
+			str:  "Age != 42",
+			want: []string{"Bob", "Jenny", "Michael", "Jane"},
+		},
+		{
+			name: "lt",
+			p:    memberAge.Lt(26),
+			str:  "Age < 26",
+			want: []string{"Michael"},
+		},
+		{
+			name: "le",
+			p:    memberAge.Le(26),
+			str:  "Age <= 26",
+			want: []string{"Jenny", "Michael"},
+		},
+		{
+			name: "gt",
+			p:    memberScore.Gt(8.25),
+			str:  "Score > 8.25",
+			want: []string{"Jenny", "Michael"},
+		},
+		{
+			name: "ge",
+			p:    memberScore.Ge(9),
+			str:  "Score >= 9.0",
+			want: []string{"Jenny", "Michael"},
+		},
+		{
+			name: "in",
+			p:    memberName.In("Jane", "O'Neil"),
+			str:  "Name IN ('Jane', 'O''Neil')",
+			want: []string{"Jane"},
+		},
+		{
+			name: "empty in",
+			p:    memberName.In(),
+			str:  "Name IN ()",
+			want: []string{},
+		},
+		{
+			name: "between",
+			p:    memberAge.Between(-1, 26),
+			str:  "Age BETWEEN -1 AND 26",
+			want: []string{"Jenny", "Michael"},
+		},
+		{
+			name: "and",
+			p:    And(memberAge.Gt(18), memberScore.Lt(8), memberName.Ne("Bob")),
+			str:  "Age > 18 AND Score < 8.0 AND Name != 'Bob'",
+			want: []string{"John"},
+		},
+		{
+			name: "or of and",
+			p:    Or(memberName.Eq("Bob"), And(memberAge.Lt(30), Not(memberName.Eq("Michael")))),
+			str:  "Name = 'Bob' OR (Age < 30 AND NOT Name = 'Michael')",
+			want: []string{"Bob", "Jenny"},
+		},
+		{
+			name: "not of or",
+			p:    Not(Or(memberAge.Lt(30), memberAge.Gt(40))),
+			str:  "NOT (Age < 30 OR Age > 40)",
+			want: []string{"Bob", "Jane"},
+		},
+		{
+			name: "empty and",
+			p:    And[member](),
+			str:  "TRUE",
+			want: []string{"Bob", "Jenny", "John", "Michael", "Jane"},
+		},
+		{
+			name: "empty or",
+			p:    Or[member](),
+			str:  "FALSE",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.String(); got != tt.str {
+				t.Errorf("Predicate.String() = %q, want %q", got, tt.str)
+			}
+			got := members.Clone().Where(tt.p.Test)
+			if names := memberNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Where(Predicate.Test) = %v, want %v", names, tt.want)
+			}
+			if tt.name == "empty in" {
+				// The query language has no empty lists.
+				return
+			}
+			// The printed predicate must select the same elements.
+			got, err := Run(members, "WHERE "+tt.str)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if names := memberNames(got); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Run() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredicate_MarshalText(t *testing.T) {
+	p := And(memberAge.Ge(18), memberName.In("Bob", "Jane"))
+	got, err := p.MarshalText()
+	if err != nil {
+		t.Fatalf("Predicate.MarshalText() error = %v", err)
+	}
+	if want := "Age >= 18 AND Name IN ('Bob', 'Jane')"; string(got) != want {
+		t.Errorf("Predicate.MarshalText() = %s, want %s", got, want)
+	}
+	if n := members.Count(p.Test); n != 2 {
+		t.Errorf("Count(Predicate.Test) = %d, want 2", n)
+	}
+}
+
+func TestField_Less(t *testing.T) {
+	got := members.Clone().Sort(memberAge.Less)
+	want := []string{"Michael", "Jenny", "Bob", "Jane", "John"}
+	if names := memberNames(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("Sort(Field.Less) = %v, want %v", names, want)
+	}
+	got = OrderByFunc(members, memberScore.Compare).ThenByFunc(memberName.Compare).ToQuery()
+	want = []string{"John", "Bob", "Jane", "Jenny", "Michael"}
+	if names := memberNames(got); !reflect.DeepEqual(names, want) {
+		t.Errorf("OrderByFunc(Field.Compare) = %v, want %v", names, want)
+	}
+}
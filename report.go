@@ -0,0 +1,124 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Report runs the aggregation query spec over the elements of q and
+// returns its rows.
+//
+// A spec is a query of the SliceQL language, see Statement, typically
+// with aggregate functions and a GROUP BY clause:
+//
+//	SELECT Dept, COUNT(*) AS n, AVG(Age) AS age GROUP BY Dept HAVING COUNT(*) > 3 ORDER BY n DESC
+//
+// Every row maps the names of the columns, or their aliases, to their
+// values, as described for Program.Rows. Errors in the spec are returned
+// as *ParseError values that point at the offending column of spec.
+func Report[E any](q *Query[E], spec string) ([]map[string]any, error) {
+	p, err := Compile[E](spec)
+	if err != nil {
+		return nil, err
+	}
+	return p.Rows(q), nil
+}
+
+// ReportInto runs the aggregation query spec over the elements of q like
+// Report and stores every row in a new value of the struct type R.
+//
+// Every column is stored in the field of R with the same name, resolved
+// like the fields of E and compared case-insensitively if there is no
+// exact match. Numbers are converted to the type of the field, and NULL
+// values leave the field at its zero value. It is an error if a column
+// has no field, or a field cannot hold the values of its column, such
+// as an integer field for the floating-point values of AVG, which would
+// be truncated, an int8 field for a SUM of 300, or an unsigned field for
+// a negative value.
+func ReportInto[E, R any](q *Query[E], spec string) ([]R, error) {
+	p, err := Compile[E](spec)
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeOf((*R)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sliceql: cannot report into non-struct type %v", typ)
+	}
+	sch := schemaOf(typ)
+	fields := make([]*schemaField, len(p.columns))
+	for i, c := range p.columns {
+		f, ok := sch.fold(c.name)
+		if !ok {
+			return nil, errorAt(c.pos, "column %q has no field in %v", c.name, typ)
+		}
+		if cl := classOf(f.typ); c.class != classAny && c.class != classOther && cl != classAny && cl != c.class {
+			return nil, errorAt(c.pos, "cannot store column %q of type %v in field %s of type %v", c.name, c.class, f.goName, f.typ)
+		}
+		fields[i] = f
+	}
+	rows := p.Rows(q)
+	result := make([]R, len(rows))
+	for i, row := range rows {
+		v := reflect.ValueOf(&result[i]).Elem()
+		for j, c := range p.columns {
+			if err := fields[j].set(v, row[c.name]); err != nil {
+				return nil, fmt.Errorf("sliceql: column %q: %w", c.name, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// store sets the field f to the value v, converting it to the type of
+// the field and allocating pointers as needed. A nil v leaves f unchanged.
+func store(f reflect.Value, v any) error {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	for f.Kind() == reflect.Pointer && !rv.Type().AssignableTo(f.Type()) {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		f = f.Elem()
+	}
+	switch {
+	case rv.Type().AssignableTo(f.Type()):
+		f.Set(rv)
+	case rv.CanFloat() && (f.CanInt() || f.CanUint()):
+		// Converting a float to an integer would silently truncate it.
+		return fmt.Errorf("cannot store %v in field of type %v without truncating", rv.Type(), f.Type())
+	case classOf(rv.Type()) == classOf(f.Type()) && rv.Type().ConvertibleTo(f.Type()):
+		if overflows(f, rv) {
+			// Converting the number would silently wrap it around.
+			return fmt.Errorf("cannot store %v in field of type %v without overflow", rv, f.Type())
+		}
+		f.Set(rv.Convert(f.Type()))
+	default:
+		return fmt.Errorf("cannot store %v in field of type %v", rv.Type(), f.Type())
+	}
+	return nil
+}
+
+// overflows reports whether the number rv cannot be represented by the
+// numeric field f.
+func overflows(f, rv reflect.Value) bool {
+	switch {
+	case rv.CanInt() && f.CanInt():
+		return f.OverflowInt(rv.Int())
+	case rv.CanInt() && f.CanUint():
+		return rv.Int() < 0 || f.OverflowUint(uint64(rv.Int()))
+	case rv.CanUint() && f.CanInt():
+		return rv.Uint() > math.MaxInt64 || f.OverflowInt(int64(rv.Uint()))
+	case rv.CanUint() && f.CanUint():
+		return f.OverflowUint(rv.Uint())
+	case rv.CanFloat() && f.CanFloat():
+		return f.OverflowFloat(rv.Float())
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Report(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []map[string]any
+	}{
+		{
+			name: "group by",
+			src:  "SELECT Active, COUNT(*) AS n GROUP BY Active",
+			want: []map[string]any{
+				{"Active": true, "n": int64(3)},
+				{"Active": false, "n": int64(2)},
+			},
+		},
+		{
+			name: "default columns",
+			src:  "GROUP BY Address.City",
+			want: []map[string]any{
+				{"Address.City": "Berlin", "COUNT(*)": int64(2)},
+				{"Address.City": nil, "COUNT(*)": int64(1)},
+				{"Address.City": "Paris", "COUNT(*)": int64(1)},
+				{"Address.City": "Rome", "COUNT(*)": int64(1)},
+			},
+		},
+		{
+			name: "aggregates",
+			src:  "SELECT SUM(Age) AS sum, AVG(Score) AS avg, MIN(Name) AS min, MAX(Age) AS max",
+			want: []map[string]any{
+				{"sum": int64(151), "avg": 7.95, "min": "Bob", "max": int64(42)},
+			},
+		},
+		{
+			name: "nulls are skipped",
+			src:  "SELECT COUNT(Address.City) AS n, MIN(Address.City) AS min WHERE Age < 30",
+			want: []map[string]any{
+				{"n": int64(1), "min": "Berlin"},
+			},
+		},
+		{
+			name: "empty input",
+			src:  "SELECT COUNT(*) AS n, SUM(Age) AS sum WHERE Age > 100",
+			want: []map[string]any{
+				{"n": int64(0), "sum": nil},
+			},
+		},
+		{
+			name: "having and order by alias",
+			src:  "SELECT Address.City AS city, AVG(Age) AS age GROUP BY Address.City HAVING COUNT(*) > 1 OR MAX(Age) > 40 ORDER BY age DESC",
+			want: []map[string]any{
+				{"city": "Paris", "age": 42.0},
+				{"city": "Berlin", "age": 24.0},
+			},
+		},
+		{
+			name: "order by aggregate, limit and offset",
+			src:  "SELECT Active GROUP BY Active ORDER BY SUM(Score) LIMIT 1 OFFSET 1",
+			want: []map[string]any{
+				{"Active": true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Report(members, tt.src)
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Report() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Report_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		column int
+	}{
+		{
+			name:   "field not grouped",
+			src:    "SELECT Name, COUNT(*) GROUP BY Active",
+			column: 8,
+		},
+		{
+			name:   "aggregate in where",
+			src:    "WHERE COUNT(*) > 1",
+			column: 7,
+		},
+		{
+			name:   "aggregate in group by",
+			src:    "GROUP BY MAX(Age)",
+			column: 10,
+		},
+		{
+			name:   "nested aggregate",
+			src:    "SELECT MAX(COUNT(*))",
+			column: 12,
+		},
+		{
+			name:   "sum of strings",
+			src:    "SELECT SUM(Name)",
+			column: 12,
+		},
+		{
+			name:   "non-boolean having",
+			src:    "GROUP BY Active HAVING COUNT(*)",
+			column: 24,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Report(members, tt.src)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Report() error = %v, want *ParseError", err)
+			}
+			if perr.Column != tt.column {
+				t.Errorf("Report() error = %v, want column %d", err, tt.column)
+			}
+		})
+	}
+}
+
+func Test_ReportInto(t *testing.T) {
+	type cityStats struct {
+		City  *string
+		Count int
+		Age   float32
+	}
+	got, err := ReportInto[member, cityStats](members, "SELECT Address.City AS city, COUNT(*) AS count, AVG(Age) AS Age GROUP BY Address.City ORDER BY city")
+	if err != nil {
+		t.Fatalf("ReportInto() error = %v", err)
+	}
+	berlin, paris, rome := "Berlin", "Paris", "Rome"
+	want := []cityStats{
+		{nil, 1, 26},
+		{&berlin, 2, 24},
+		{&paris, 1, 42},
+		{&rome, 1, 35},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReportInto() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name   string
+		src    string
+		column int
+	}{
+		{
+			name:   "missing field",
+			src:    "SELECT COUNT(*) AS total",
+			column: 8,
+		},
+		{
+			name:   "type mismatch",
+			src:    "SELECT MIN(Name) AS count",
+			column: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReportInto[member, cityStats](members, tt.src)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ReportInto() error = %v, want *ParseError", err)
+			}
+			if perr.Column != tt.column {
+				t.Errorf("ReportInto() error = %v, want column %d", err, tt.column)
+			}
+		})
+	}
+}
+
+func Test_ReportInto_Truncate(t *testing.T) {
+	type cityStats struct {
+		City  *string
+		Count int
+	}
+	_, err := ReportInto[member, cityStats](members, "SELECT Address.City AS city, AVG(Age) AS count GROUP BY Address.City")
+	if err == nil || !strings.Contains(err.Error(), "without truncating") {
+		t.Errorf("ReportInto() error = %v, want a truncation error", err)
+	}
+}
+
+func Test_ReportInto_Overflow(t *testing.T) {
+	type small struct {
+		Total int8
+	}
+	_, err := ReportInto[member, small](members, "SELECT SUM(Age) AS Total")
+	if err == nil || !strings.Contains(err.Error(), "without overflow") {
+		t.Errorf("ReportInto() error = %v, want an overflow error", err)
+	}
+	type wide struct {
+		Total uint16
+	}
+	got, err := ReportInto[member, wide](members, "SELECT SUM(Age) AS Total")
+	if err != nil || !reflect.DeepEqual(got, []wide{{151}}) {
+		t.Errorf("ReportInto() = %v, %v, want [{151}], <nil>", got, err)
+	}
+}
+
+func Test_overflows(t *testing.T) {
+	var (
+		i8  int8
+		u8  uint8
+		i64 int64
+		f32 float32
+	)
+	tests := []struct {
+		name  string
+		field any
+		v     any
+		want  bool
+	}{
+		{"int fits", &i8, int64(-128), false},
+		{"int too large", &i8, int64(128), true},
+		{"negative into uint", &u8, int64(-1), true},
+		{"int fits uint", &u8, int64(255), false},
+		{"uint too large for int", &i64, uint64(math.MaxUint64), true},
+		{"uint too large", &u8, uint64(256), true},
+		{"float too large", &f32, math.MaxFloat64, true},
+		{"float fits", &f32, 1.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := reflect.ValueOf(tt.field).Elem()
+			if got := overflows(f, reflect.ValueOf(tt.v)); got != tt.want {
+				t.Errorf("overflows(%v, %v) = %t, want %t", f.Type(), tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Run_Grouped(t *testing.T) {
+	got, err := Run(members, "GROUP BY Active HAVING COUNT(*) < 3")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if names, want := memberNames(got), []string{"Jenny", "Jane"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Run() = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// schema maps names to the fields of a struct type. It is shared by
+// every feature that resolves field names at run time.
+//
+// The name of a field is taken from its sliceql tag, falling back to its
+// json tag and then to its Go name:
+//
+//	type Person struct {
+//		Name    string   `sliceql:"name"`
+//		Email   string   `json:"email,omitempty"`
+//		Address *Address // named Address
+//		Secret  string   `sliceql:"-"` // not visible
+//	}
+//
+// The fields of embedded structs are promoted like in encoding/json:
+// a field of a shallower struct hides those of deeper ones, and fields
+// of the same depth with the same name hide each other. An embedded
+// struct with a tag name is a field of its own.
+type schema struct {
+	typ reflect.Type
+	// fields are the visible fields in declaration order.
+	fields []*schemaField
+	byName map[string]*schemaField
+	byGo   map[string]*schemaField
+}
+
+// schemaField is a visible field of a struct type.
+type schemaField struct {
+	name   string
+	goName string
+	typ    reflect.Type
+	index  []int
+	// omitEmpty reports whether the field is left out of rows
+	// if it holds an empty value.
+	omitEmpty bool
+}
+
+// schemas caches the schema of every struct type resolved so far.
+var schemas sync.Map // map[reflect.Type]*schema
+
+// schemaOf returns the schema of the struct type t.
+func schemaOf(t reflect.Type) *schema {
+	if s, ok := schemas.Load(t); ok {
+		return s.(*schema)
+	}
+	s, _ := schemas.LoadOrStore(t, newSchema(t))
+	return s.(*schema)
+}
+
+// newSchema resolves the visible fields of the struct type t.
+func newSchema(t reflect.Type) *schema {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	s := &schema{typ: t, byName: make(map[string]*schemaField), byGo: make(map[string]*schemaField)}
+	hidden := make(map[string]bool)
+	hiddenGo := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	for level := []embedded{{typ: t}}; len(level) > 0; {
+		var next []embedded
+		names := make(map[string][]*schemaField)
+		goNames := make(map[string][]*schemaField)
+		for _, e := range level {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				name, omitEmpty, ok := fieldTag(sf)
+				if !ok {
+					continue
+				}
+				index := append(slices.Clip(e.index), i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if name == "" && ft.Kind() == reflect.Struct {
+						next = append(next, embedded{typ: ft, index: index})
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = sf.Name
+				}
+				f := &schemaField{name: name, goName: sf.Name, typ: sf.Type, index: index, omitEmpty: omitEmpty}
+				names[name] = append(names[name], f)
+				goNames[sf.Name] = append(goNames[sf.Name], f)
+			}
+		}
+		promote(s.byName, hidden, names)
+		promote(s.byGo, hiddenGo, goNames)
+		level = next
+	}
+	for _, f := range s.byName {
+		s.fields = append(s.fields, f)
+	}
+	slices.SortFunc(s.fields, func(a, b *schemaField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return s
+}
+
+// promote adds the fields found at one depth of embedding to m, unless
+// a shallower field of the same name hides them. Fields of the same
+// depth with the same name hide each other.
+func promote(m map[string]*schemaField, hidden map[string]bool, level map[string][]*schemaField) {
+	for name, fs := range level {
+		if _, ok := m[name]; ok || hidden[name] {
+			continue
+		}
+		if len(fs) > 1 {
+			hidden[name] = true
+			continue
+		}
+		m[name] = fs[0]
+	}
+}
+
+// fieldTag returns the name and options of the field f from its
+// sliceql or json tag. It returns ok false if the field is skipped.
+func fieldTag(f reflect.StructField) (name string, omitEmpty, ok bool) {
+	tag, found := f.Tag.Lookup("sliceql")
+	if !found {
+		tag = f.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		omitEmpty = omitEmpty || opt == "omitempty"
+	}
+	if name == "" && found {
+		// A sliceql tag with options only keeps the json name.
+		name, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			name = ""
+		}
+	}
+	return name, omitEmpty, true
+}
+
+// field returns the field called name, looking up the Go names of
+// the fields if no field has that name.
+func (s *schema) field(name string) (*schemaField, bool) {
+	if f, ok := s.byName[name]; ok {
+		return f, true
+	}
+	f, ok := s.byGo[name]
+	return f, ok
+}
+
+// fold returns the field called name like field, ignoring case if no
+// field matches exactly.
+func (s *schema) fold(name string) (*schemaField, bool) {
+	if f, ok := s.field(name); ok {
+		return f, true
+	}
+	for _, f := range s.fields {
+		if strings.EqualFold(f.name, name) || strings.EqualFold(f.goName, name) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// resolve resolves the dotted field path against the schema.
+//
+// It returns a function that reads the field from a struct value and the
+// type of the field. The function returns the zero Value if a pointer on
+// the path is nil.
+func (s *schema) resolve(path string) (func(reflect.Value) reflect.Value, reflect.Type, error) {
+	var fields []*schemaField
+	for i, name := range splitPath(path) {
+		if i > 0 {
+			t := fields[i-1].typ
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return nil, nil, fmt.Errorf("%q is not a struct", joinPath(path, i))
+			}
+			s = schemaOf(t)
+		}
+		f, ok := s.field(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown field %q", joinPath(path, i+1))
+		}
+		fields = append(fields, f)
+	}
+	return func(v reflect.Value) reflect.Value {
+		for _, f := range fields {
+			v = f.get(v)
+		}
+		return v
+	}, fields[len(fields)-1].typ, nil
+}
+
+// get reads the field from the struct value v, or a pointer to it.
+// It returns the zero Value if v or a pointer on the way is nil.
+func (f *schemaField) get(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v
+	}
+	fv, err := v.FieldByIndexErr(f.index)
+	if err != nil {
+		// A nil embedded pointer hides the field.
+		return reflect.Value{}
+	}
+	return fv
+}
+
+// set stores x in the field of the addressable struct value v,
+// allocating nil embedded pointers on the way.
+func (f *schemaField) set(v reflect.Value, x any) error {
+	for i, n := range f.index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return fmt.Errorf("cannot set field %s through nil embedded pointer", f.goName)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(n)
+	}
+	if !v.CanSet() {
+		return fmt.Errorf("cannot set field %s", f.goName)
+	}
+	return store(v, x)
+}
+
+// isEmpty reports whether x is an empty value as defined by encoding/json:
+// false, 0, a nil pointer or interface, and an empty array, slice, map
+// or string.
+func isEmpty(x any) bool {
+	if x == nil {
+		return true
+	}
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"testing"
+)
+
+type audit struct {
+	Created int `json:"created"`
+	Updated int `sliceql:"updated,omitempty"`
+}
+
+type location struct {
+	City string `json:"city"`
+}
+
+type account struct {
+	ID     string   `sliceql:"id"`
+	Name   string   `json:"name,omitempty"`
+	Email  string   `sliceql:",omitempty" json:"mail"`
+	Secret string   `sliceql:"-"`
+	Token  string   `json:"-"`
+	Home   location `sliceql:"home"`
+	Work   *location
+	*audit
+	internal int
+}
+
+func Test_schemaOf(t *testing.T) {
+	s := schemaOf(reflect.TypeOf(account{}))
+	if s != schemaOf(reflect.TypeOf(account{})) {
+		t.Errorf("schemaOf() is not cached")
+	}
+	var names []string
+	for _, f := range s.fields {
+		names = append(names, f.name)
+	}
+	want := []string{"id", "name", "mail", "home", "Work", "created", "updated"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("schemaOf().fields = %v, want %v", names, want)
+	}
+	if f, _ := s.field("mail"); !f.omitEmpty {
+		t.Errorf("schemaOf().field(mail).omitEmpty = false, want true")
+	}
+	if f, ok := s.field("Email"); !ok || f.name != "mail" {
+		t.Errorf("schemaOf().field(Email) = %v, %t, want field mail", f, ok)
+	}
+	for _, name := range []string{"Secret", "Token", "internal", "audit", "Home.City"} {
+		if _, ok := s.field(name); ok {
+			t.Errorf("schemaOf().field(%s) found, want none", name)
+		}
+	}
+}
+
+func Test_Run_Tags(t *testing.T) {
+	q := &Query[account]{
+		{ID: "a", Name: "Ann", Home: location{"Berlin"}, Work: &location{"Paris"}, audit: &audit{Created: 1}},
+		{ID: "b", Name: "Ben", Email: "ben@example.com", Home: location{"Rome"}},
+		{ID: "c", Home: location{"Berlin"}, audit: &audit{Created: 3, Updated: 4}},
+	}
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "tag name",
+			src:  "WHERE name = 'Ann'",
+			want: []string{"a"},
+		},
+		{
+			name: "go name",
+			src:  "WHERE Name = 'Ann'",
+			want: []string{"a"},
+		},
+		{
+			name: "nested tag name",
+			src:  "WHERE home.city = 'Berlin'",
+			want: []string{"a", "c"},
+		},
+		{
+			name: "pointer path",
+			src:  "WHERE Work.city = NULL",
+			want: []string{"b", "c"},
+		},
+		{
+			name: "embedded pointer",
+			src:  "WHERE created >= 1 ORDER BY updated DESC",
+			want: []string{"c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Run(q, tt.src)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			ids := Select(got, func(a account) string {
+				return a.ID
+			}).ToSlice()
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Run() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	for _, src := range []string{"WHERE Secret = ''", "WHERE Token = ''", "WHERE home.City.x = 1"} {
+		if _, err := Compile[account](src); err == nil {
+			t.Errorf("Compile(%q) error = nil, want error", src)
+		}
+	}
+}
+
+func TestProgram_Rows_Tags(t *testing.T) {
+	q := &Query[account]{
+		{ID: "a", Name: "Ann", audit: &audit{Created: 1}},
+		{ID: "b", Email: "ben@example.com"},
+	}
+	p, err := Compile[account]("SELECT *")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	want := []map[string]any{
+		{"id": "a", "name": "Ann", "home": location{}, "Work": nil, "created": 1},
+		{"id": "b", "mail": "ben@example.com", "home": location{}, "Work": nil, "created": nil},
+	}
+	if got := p.Rows(q); !reflect.DeepEqual(got, want) {
+		t.Errorf("Program.Rows() = %#v, want %#v", got, want)
+	}
+}
+
+func Test_ReportInto_Tags(t *testing.T) {
+	type cityCount struct {
+		City  string `sliceql:"city"`
+		Count int64  `json:"n"`
+		audit
+	}
+	q := &Query[account]{
+		{ID: "a", Home: location{"Berlin"}},
+		{ID: "b", Home: location{"Rome"}},
+		{ID: "c", Home: location{"Berlin"}},
+	}
+	got, err := ReportInto[account, cityCount](q, "SELECT home.city AS city, COUNT(*) AS n, MAX(id) AS created GROUP BY home.city")
+	if err == nil {
+		t.Fatalf("ReportInto() = %v, want error", got)
+	}
+	got, err = ReportInto[account, cityCount](q, "SELECT home.city AS city, COUNT(*) AS n, COUNT(*) AS updated GROUP BY home.city")
+	if err != nil {
+		t.Fatalf("ReportInto() error = %v", err)
+	}
+	want := []cityCount{
+		{"Berlin", 2, audit{Updated: 2}},
+		{"Rome", 1, audit{Updated: 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReportInto() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+// Select projects each element of the Query into a new form.
+//
+// Go methods cannot declare type parameters, so Select is a function
+// rather than a method of Query. It takes a Query of E and a function f
+// that maps an element to a value of type R.
+//
+// The function returns a pointer to a new Query of R. The source Query
+// is not modified. A nil f yields an empty Query.
+func Select[E, R any](q *Query[E], f func(E) R) *Query[R] {
+	if f == nil {
+		return &Query[R]{}
+	}
+	result := Query[R](make([]R, len(*q)))
+	for i, e := range *q {
+		result[i] = f(e)
+	}
+	return &result
+}
+
+// SelectIndexed projects each element of the Query into a new form
+// using the element and its index.
+//
+// The function returns a pointer to a new Query of R. The source Query
+// is not modified. A nil f yields an empty Query.
+func SelectIndexed[E, R any](q *Query[E], f func(int, E) R) *Query[R] {
+	if f == nil {
+		return &Query[R]{}
+	}
+	result := Query[R](make([]R, len(*q)))
+	for i, e := range *q {
+		result[i] = f(i, e)
+	}
+	return &result
+}
+
+// SelectMany projects each element of the Query to a slice and
+// flattens the resulting slices into a single Query.
+//
+// The order of the elements is preserved: the elements produced for
+// the first element come first, and so on.
+//
+// The function returns a pointer to a new Query of R. The source Query
+// is not modified. A nil f yields an empty Query.
+func SelectMany[E, R any](q *Query[E], f func(E) []R) *Query[R] {
+	result := Query[R](make([]R, 0))
+	if f == nil {
+		return &result
+	}
+	for _, e := range *q {
+		result = append(result, f(e)...)
+	}
+	return &result
+}
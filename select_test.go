@@ -0,0 +1,143 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_Select(t *testing.T) {
+	type args struct {
+		q *Query[int]
+		f func(int) string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[string]
+	}{
+		{
+			name: "nil selector",
+			args: args{
+				q: &Query[int]{1, 2, 3},
+				f: nil,
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q: &Query[int]{},
+				f: strconv.Itoa,
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "non-empty slice",
+			args: args{
+				q: &Query[int]{1, 2, 3},
+				f: strconv.Itoa,
+			},
+			want: &Query[string]{"1", "2", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Select(tt.args.q, tt.args.f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SelectIndexed(t *testing.T) {
+	type args struct {
+		q *Query[string]
+		f func(int, string) string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[string]
+	}{
+		{
+			name: "nil selector",
+			args: args{
+				q: &Query[string]{"a", "b"},
+				f: nil,
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "non-empty slice",
+			args: args{
+				q: &Query[string]{"a", "b"},
+				f: func(i int, s string) string {
+					return strconv.Itoa(i) + s
+				},
+			},
+			want: &Query[string]{"0a", "1b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectIndexed(tt.args.q, tt.args.f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectIndexed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SelectMany(t *testing.T) {
+	type args struct {
+		q *Query[int]
+		f func(int) []int
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[int]
+	}{
+		{
+			name: "nil selector",
+			args: args{
+				q: &Query[int]{1, 2},
+				f: nil,
+			},
+			want: &Query[int]{},
+		},
+		{
+			name: "flatten",
+			args: args{
+				q: &Query[int]{1, 2, 3},
+				f: func(e int) []int {
+					return Create(e, func(int) int {
+						return e
+					}).ToSlice()
+				},
+			},
+			want: &Query[int]{1, 2, 2, 3, 3, 3},
+		},
+		{
+			name: "empty results",
+			args: args{
+				q: &Query[int]{1, 2},
+				f: func(int) []int {
+					return nil
+				},
+			},
+			want: &Query[int]{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectMany(tt.args.q, tt.args.f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectMany() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+// identity returns its argument unchanged.
+func identity[E any](e E) E {
+	return e
+}
+
+// keySet returns the set of keys of the elements in q.
+func keySet[E any, K comparable](q *Query[E], key func(E) K) map[K]struct{} {
+	m := make(map[K]struct{}, len(*q))
+	for _, e := range *q {
+		m[key(e)] = struct{}{}
+	}
+	return m
+}
+
+// Distinct returns the distinct elements of the Query.
+//
+// The order of first occurrence is preserved.
+// The function returns a pointer to a new Query. The source Query
+// is not modified.
+func Distinct[E comparable](q *Query[E]) *Query[E] {
+	return DistinctBy(q, identity[E])
+}
+
+// DistinctBy returns the elements of the Query with distinct keys,
+// as computed by key.
+//
+// Of the elements that share a key only the first is kept, and the
+// order of first occurrence is preserved. A nil key yields an empty Query.
+func DistinctBy[E any, K comparable](q *Query[E], key func(E) K) *Query[E] {
+	return UnionBy(q, &Query[E]{}, key)
+}
+
+// Union returns the distinct elements of both queries,
+// the elements of a first.
+//
+// The order of first occurrence is preserved.
+func Union[E comparable](a, b *Query[E]) *Query[E] {
+	return UnionBy(a, b, identity[E])
+}
+
+// UnionBy returns the elements of both queries with distinct keys,
+// as computed by key, the elements of a first.
+//
+// Of the elements that share a key only the first is kept, and the
+// order of first occurrence is preserved. A nil key yields an empty Query.
+func UnionBy[E any, K comparable](a, b *Query[E], key func(E) K) *Query[E] {
+	result := Query[E](make([]E, 0))
+	if key == nil {
+		return &result
+	}
+	seen := make(map[K]struct{})
+	for _, q := range []*Query[E]{a, b} {
+		for _, e := range *q {
+			k := key(e)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			result = append(result, e)
+		}
+	}
+	return &result
+}
+
+// Intersect returns the distinct elements of a that also occur in b.
+//
+// The order of first occurrence in a is preserved.
+func Intersect[E comparable](a, b *Query[E]) *Query[E] {
+	return IntersectBy(a, b, identity[E])
+}
+
+// IntersectBy returns the elements of a with distinct keys, as computed
+// by key, whose key also occurs in b.
+//
+// The order of first occurrence in a is preserved.
+// A nil key yields an empty Query.
+func IntersectBy[E any, K comparable](a, b *Query[E], key func(E) K) *Query[E] {
+	return filterByKeys(a, b, key, true)
+}
+
+// Except returns the distinct elements of a that do not occur in b.
+//
+// The order of first occurrence in a is preserved.
+func Except[E comparable](a, b *Query[E]) *Query[E] {
+	return ExceptBy(a, b, identity[E])
+}
+
+// ExceptBy returns the elements of a with distinct keys, as computed
+// by key, whose key does not occur in b.
+//
+// The order of first occurrence in a is preserved.
+// A nil key yields an empty Query.
+func ExceptBy[E any, K comparable](a, b *Query[E], key func(E) K) *Query[E] {
+	return filterByKeys(a, b, key, false)
+}
+
+// filterByKeys returns the elements of a with distinct keys whose
+// membership in the keys of b equals in.
+func filterByKeys[E any, K comparable](a, b *Query[E], key func(E) K, in bool) *Query[E] {
+	result := Query[E](make([]E, 0))
+	if key == nil {
+		return &result
+	}
+	keys := keySet(b, key)
+	seen := make(map[K]struct{})
+	for _, e := range *a {
+		k := key(e)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		if _, ok := keys[k]; ok == in {
+			result = append(result, e)
+		}
+	}
+	return &result
+}
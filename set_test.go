@@ -0,0 +1,142 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Distinct(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query[int]
+		want *Query[int]
+	}{
+		{
+			name: "empty slice",
+			q:    &Query[int]{},
+			want: &Query[int]{},
+		},
+		{
+			name: "first occurrence order",
+			q:    &Query[int]{3, 1, 3, 2, 1},
+			want: &Query[int]{3, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distinct(tt.q); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Distinct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DistinctBy(t *testing.T) {
+	type args struct {
+		q   *Query[string]
+		key func(string) string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Query[string]
+	}{
+		{
+			name: "nil key",
+			args: args{
+				q: &Query[string]{"a"},
+			},
+			want: &Query[string]{},
+		},
+		{
+			name: "case insensitive",
+			args: args{
+				q:   &Query[string]{"Go", "go", "Rust", "GO", "rust"},
+				key: strings.ToLower,
+			},
+			want: &Query[string]{"Go", "Rust"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistinctBy(tt.args.q, tt.args.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DistinctBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SetOperations(t *testing.T) {
+	a := &Query[int]{1, 2, 2, 3, 4}
+	b := &Query[int]{4, 5, 3, 5}
+	tests := []struct {
+		name string
+		f    func(a, b *Query[int]) *Query[int]
+		want *Query[int]
+	}{
+		{
+			name: "union",
+			f:    Union[int],
+			want: &Query[int]{1, 2, 3, 4, 5},
+		},
+		{
+			name: "intersect",
+			f:    Intersect[int],
+			want: &Query[int]{3, 4},
+		},
+		{
+			name: "except",
+			f:    Except[int],
+			want: &Query[int]{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f(a, b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	if want := (&Query[int]{1, 2, 2, 3, 4}); !reflect.DeepEqual(a, want) {
+		t.Errorf("source modified to %v", a)
+	}
+}
+
+func Test_SetOperationsBy(t *testing.T) {
+	a := &Query[Person]{{"Bob", 31}, {"Jenny", 26}, {"Bobby", 31}, {"John", 42}}
+	b := &Query[Person]{{"Michael", 42}, {"Ann", 17}}
+	age := func(p Person) int { return p.Age }
+	tests := []struct {
+		name string
+		f    func(a, b *Query[Person], key func(Person) int) *Query[Person]
+		want *Query[Person]
+	}{
+		{
+			name: "union",
+			f:    UnionBy[Person, int],
+			want: &Query[Person]{{"Bob", 31}, {"Jenny", 26}, {"John", 42}, {"Ann", 17}},
+		},
+		{
+			name: "intersect",
+			f:    IntersectBy[Person, int],
+			want: &Query[Person]{{"John", 42}},
+		},
+		{
+			name: "except",
+			f:    ExceptBy[Person, int],
+			want: &Query[Person]{{"Bob", 31}, {"Jenny", 26}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f(a, b, age); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%sBy() = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
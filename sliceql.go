@@ -34,8 +34,11 @@ func Create[E any](n int, f func(int) E) *Query[E] {
 // NewQuery creates a new Query object.
 //
 // It takes a slice s of elements of type E and returns a pointer to a Query object.
+// The slice is not copied: the Query shares its backing array with v, so
+// methods that modify elements in place, such as Each, Sort and Reverse,
+// also modify v. Use Clone for an independent copy, or View for an
+// immutable query.
 func NewQuery[E any](v []E) *Query[E] {
-	// Make shallow copy of slice elements.
 	q := Query[E](v)
 	return &q
 }
@@ -83,14 +86,24 @@ func (q *Query[E]) Any(f func(E) bool) bool {
 //
 // Returns:
 // - E: the element at the specified index.
+//
+// It panics if the Query is empty or the index is out of bounds.
+// Use ElementAt or AtOr to handle these cases without a panic.
 func (q *Query[E]) At(i int) E {
-	if len(*q) < 1 {
-		panic("sliceql.At: empty list")
+	e, err := q.elementAt("At", i)
+	if err != nil {
+		panic(err)
 	}
-	if i < 0 || i >= len(*q) {
-		panic("sliceql.At: index out of bounds")
+	return e
+}
+
+// AtOr returns the element at the specified index,
+// or def if the index is out of bounds.
+func (q *Query[E]) AtOr(i int, def E) E {
+	if e, err := q.elementAt("AtOr", i); err == nil {
+		return e
 	}
-	return (*q)[i]
+	return def
 }
 
 // Contains checks if the query contains an element that satisfies the given function.
@@ -132,9 +145,30 @@ func (q *Query[E]) Each(f func(E) E) *Query[E] {
 	for i := range *q {
 		(*q)[i] = f((*q)[i])
 	}
+	q.touch()
 	return q
 }
 
+// ElementAt returns the element at the specified index.
+//
+// The error wraps ErrEmpty if the Query is empty,
+// or ErrOutOfRange if the index is out of bounds.
+func (q *Query[E]) ElementAt(i int) (E, error) {
+	return q.elementAt("ElementAt", i)
+}
+
+// elementAt returns the element at index i or an error naming op.
+func (q *Query[E]) elementAt(op string, i int) (E, error) {
+	var e E
+	if len(*q) < 1 {
+		return e, fmt.Errorf("sliceql.%s: %w", op, ErrEmpty)
+	}
+	if i < 0 || i >= len(*q) {
+		return e, fmt.Errorf("sliceql.%s: %w", op, ErrOutOfRange)
+	}
+	return (*q)[i], nil
+}
+
 // Equal checks if the Query is equal to the given slice using the provided equality function.
 //
 // It takes a slice of type E and a function eq that takes two parameters of type E and returns a bool.
@@ -155,9 +189,22 @@ func (q *Query[E]) Equal(v []E, eq func(E, E) bool) bool {
 //
 // No parameters.
 // Returns the element of type E.
+//
+// It panics if the Query is empty. Use TryFirst or FirstOrDefault
+// to handle an empty Query without a panic.
 func (q *Query[E]) First() E {
+	e, err := q.elementAt("First", 0)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// FirstOrDefault returns the first element of the Query,
+// or def if the Query is empty.
+func (q *Query[E]) FirstOrDefault(def E) E {
 	if len(*q) < 1 {
-		panic("sliceql.First: empty list")
+		return def
 	}
 	return (*q)[0]
 }
@@ -201,9 +248,22 @@ func (q *Query[E]) Index(f func(E) bool) int {
 //
 // It does not take any parameters.
 // It returns the type E.
+//
+// It panics if the Query is empty. Use TryLast or LastOrDefault
+// to handle an empty Query without a panic.
 func (q *Query[E]) Last() E {
+	e, err := q.elementAt("Last", len(*q)-1)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// LastOrDefault returns the last element of the Query,
+// or def if the Query is empty.
+func (q *Query[E]) LastOrDefault(def E) E {
 	if len(*q) < 1 {
-		panic("sliceql.Last: empty list")
+		return def
 	}
 	return (*q)[len(*q)-1]
 }
@@ -216,6 +276,7 @@ func (q *Query[E]) Reverse() *Query[E] {
 	for i, j := 0, len(*q)-1; i < j; i, j = i+1, j-1 {
 		(*q)[i], (*q)[j] = (*q)[j], (*q)[i]
 	}
+	q.touch()
 	return q
 }
 
@@ -223,15 +284,13 @@ func (q *Query[E]) Reverse() *Query[E] {
 //
 // n: the number of elements to skip.
 // Returns: a pointer to the modified Query.
+//
+// The count is clamped to the bounds of the Query: a negative n skips
+// nothing and an n beyond the number of elements empties the Query.
 func (q *Query[E]) Skip(n int) *Query[E] {
-	if len(*q) < 1 {
-		panic("sliceql.Skip: empty list")
-	}
-	if n < 0 || n > len(*q) {
-		panic("sliceql.Skip: index out of bounds")
-	}
-	m := min(n, len(*q))
+	m := min(max(n, 0), len(*q))
 	*q = (*q)[m:]
+	q.touch()
 	return q
 }
 
@@ -248,6 +307,7 @@ func (q *Query[E]) Sort(le func(E, E) bool) *Query[E] {
 	sort.Slice(*q, func(i, j int) bool {
 		return le((*q)[i], (*q)[j])
 	})
+	q.touch()
 	return q
 }
 
@@ -261,15 +321,13 @@ func (q *Query[E]) String() string {
 //
 // n: the number of elements to take from the Query.
 // *Query[E]: a new Query with the specified number of elements.
+//
+// The count is clamped to the bounds of the Query: a negative n empties
+// the Query and an n beyond the number of elements keeps all of them.
 func (q *Query[E]) Take(n int) *Query[E] {
-	if len(*q) < 1 {
-		panic("sliceql.Take: empty list")
-	}
-	if n < 0 || n > len(*q) {
-		panic("sliceql.Take: index out of bounds")
-	}
-	m := min(n, len(*q))
+	m := min(max(n, 0), len(*q))
 	*q = (*q)[:m]
+	q.touch()
 	return q
 }
 
@@ -281,6 +339,20 @@ func (q *Query[E]) ToSlice() []E {
 	return []E(*q)
 }
 
+// TryFirst returns the first element of the Query.
+//
+// The error wraps ErrEmpty if the Query is empty.
+func (q *Query[E]) TryFirst() (E, error) {
+	return q.elementAt("TryFirst", 0)
+}
+
+// TryLast returns the last element of the Query.
+//
+// The error wraps ErrEmpty if the Query is empty.
+func (q *Query[E]) TryLast() (E, error) {
+	return q.elementAt("TryLast", len(*q)-1)
+}
+
 // Where filters the elements in the Query based on
 // the provided test function.
 //
@@ -301,5 +373,6 @@ func (q *Query[E]) Where(f func(E) bool) *Query[E] {
 		}
 	}
 	*q = result
+	q.touch()
 	return q
 }
@@ -879,22 +879,30 @@ func TestQuery_Skip(t *testing.T) {
 		args args
 		want *Query[int]
 	}{
-		// {
-		// 	name: "negative n",
-		// 	args: args{
-		// 		q: &Query[int]{1, 2, 3, 4, 5},
-		// 		n: -1,
-		// 	},
-		// 	want: &Query[int]{},
-		// // },
-		// {
-		// 	name: "empty slice",
-		// 	args: args{
-		// 		q: &Query[int]{},
-		// 		n: 1,
-		// 	},
-		// 	want: &Query[int]{},
-		// },
+		{
+			name: "negative n",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				n: -1,
+			},
+			want: &Query[int]{1, 2, 3, 4, 5},
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q: &Query[int]{},
+				n: 1,
+			},
+			want: &Query[int]{},
+		},
+		{
+			name: "empty slice (zero n)",
+			args: args{
+				q: &Query[int]{},
+				n: 0,
+			},
+			want: &Query[int]{},
+		},
 		{
 			name: "first element",
 			args: args{
@@ -919,14 +927,14 @@ func TestQuery_Skip(t *testing.T) {
 			},
 			want: &Query[int]{},
 		},
-		// {
-		// 	name: "n overflow",
-		// 	args: args{
-		// 		q: &Query[int]{1, 2, 3, 4, 5},
-		// 		n: 10,
-		// 	},
-		// 	want: &Query[int]{},
-		// },
+		{
+			name: "n overflow",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				n: 10,
+			},
+			want: &Query[int]{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1057,22 +1065,22 @@ func TestQuery_Take(t *testing.T) {
 		args args
 		want *Query[int]
 	}{
-		// {
-		// 	name: "negative count",
-		// 	args: args{
-		// 		q: &Query[int]{1, 2, 3, 4, 5},
-		// 		n: -1,
-		// 	},
-		// 	want: &Query[int]{},
-		// },
-		// {
-		// 	name: "empty slice",
-		// 	args: args{
-		// 		q: &Query[int]{},
-		// 		n: 1,
-		// 	},
-		// 	want: &Query[int]{},
-		// },
+		{
+			name: "negative count",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				n: -1,
+			},
+			want: &Query[int]{},
+		},
+		{
+			name: "empty slice",
+			args: args{
+				q: &Query[int]{},
+				n: 1,
+			},
+			want: &Query[int]{},
+		},
 		{
 			name: "first element",
 			args: args{
@@ -1097,14 +1105,14 @@ func TestQuery_Take(t *testing.T) {
 			},
 			want: &Query[int]{1, 2, 3, 4, 5},
 		},
-		// {
-		// 	name: "index out of bounds",
-		// 	args: args{
-		// 		q: &Query[int]{1, 2, 3, 4, 5},
-		// 		n: 10,
-		// 	},
-		// 	want: &Query[int]{1, 2, 3, 4, 5},
-		// },
+		{
+			name: "index out of bounds",
+			args: args{
+				q: &Query[int]{1, 2, 3, 4, 5},
+				n: 10,
+			},
+			want: &Query[int]{1, 2, 3, 4, 5},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,260 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// A View is an immutable query over a slice.
+//
+// A View offers the operations of Query, but never writes to the slice
+// it was created from: every operation returns a new View and leaves the
+// receiver untouched. Views share their elements copy-on-write. Skip,
+// Take and Clone share the backing array of the receiver, and a new
+// array is only allocated by operations that produce different elements
+// or a different order, such as Each, Sort, Reverse or a Where that
+// drops elements.
+//
+// The zero value of a View is an empty view.
+type View[E any] struct {
+	v []E
+}
+
+// NewView creates a new View over the slice v.
+//
+// The slice is not copied. The View never modifies it, but changes made
+// to v by the caller are visible through the View. Use Query.Clone to
+// take a snapshot first if v is modified elsewhere.
+func NewView[E any](v []E) *View[E] {
+	return &View[E]{v: slices.Clip(v)}
+}
+
+// View returns an immutable View over the elements of the Query.
+//
+// The elements are not copied. Operations on the View never modify
+// the Query.
+func (q *Query[E]) View() *View[E] {
+	return NewView([]E(*q))
+}
+
+// Clone returns a new Query with a copy of the elements of the Query.
+//
+// The clone does not share its backing array with the receiver,
+// so neither is affected by mutating operations on the other.
+func (q *Query[E]) Clone() *Query[E] {
+	c := Query[E](append(make([]E, 0, len(*q)), *q...))
+	return &c
+}
+
+// Clone returns a View of the same elements.
+//
+// Since views are immutable, the elements are shared and not copied.
+func (w *View[E]) Clone() *View[E] {
+	return &View[E]{v: w.v}
+}
+
+// All checks if all elements in the View satisfy the given test.
+//
+// As with Query.All, it returns false for an empty View or a nil f.
+func (w *View[E]) All(f func(E) bool) bool {
+	return (*Query[E])(&w.v).All(f)
+}
+
+// Any checks if any element in the View satisfies the given test.
+func (w *View[E]) Any(f func(E) bool) bool {
+	return (*Query[E])(&w.v).Any(f)
+}
+
+// At returns the element at the specified index.
+//
+// It panics if the index is out of bounds.
+func (w *View[E]) At(i int) E {
+	return (*Query[E])(&w.v).At(i)
+}
+
+// AtOr returns the element at the specified index,
+// or def if the index is out of bounds.
+func (w *View[E]) AtOr(i int, def E) E {
+	return (*Query[E])(&w.v).AtOr(i, def)
+}
+
+// Contains checks if the View contains an element that satisfies f.
+func (w *View[E]) Contains(f func(E) bool) bool {
+	return (*Query[E])(&w.v).Contains(f)
+}
+
+// Count returns the number of elements in the View that satisfy f.
+func (w *View[E]) Count(f func(E) bool) int {
+	return (*Query[E])(&w.v).Count(f)
+}
+
+// Each returns a new View with every element replaced by the result of f.
+//
+// The receiver is not modified.
+func (w *View[E]) Each(f func(E) E) *View[E] {
+	v := make([]E, len(w.v))
+	for i, e := range w.v {
+		v[i] = f(e)
+	}
+	return &View[E]{v: v}
+}
+
+// ElementAt returns the element at the specified index.
+//
+// The error wraps ErrEmpty or ErrOutOfRange, as for Query.ElementAt.
+func (w *View[E]) ElementAt(i int) (E, error) {
+	return (*Query[E])(&w.v).ElementAt(i)
+}
+
+// Equal checks if the View is equal to the given slice using
+// the provided equality function.
+func (w *View[E]) Equal(v []E, eq func(E, E) bool) bool {
+	return (*Query[E])(&w.v).Equal(v, eq)
+}
+
+// First returns the first element of the View.
+//
+// It panics if the View is empty.
+func (w *View[E]) First() E {
+	return (*Query[E])(&w.v).First()
+}
+
+// FirstOrDefault returns the first element of the View,
+// or def if the View is empty.
+func (w *View[E]) FirstOrDefault(def E) E {
+	return (*Query[E])(&w.v).FirstOrDefault(def)
+}
+
+// Fold combines the elements of the View with f, starting from v.
+func (w *View[E]) Fold(v E, f func(E, E) E) E {
+	return (*Query[E])(&w.v).Fold(v, f)
+}
+
+// Index returns the index of the first element that satisfies f,
+// or -1 if there is none.
+func (w *View[E]) Index(f func(E) bool) int {
+	return (*Query[E])(&w.v).Index(f)
+}
+
+// Last returns the last element of the View.
+//
+// It panics if the View is empty.
+func (w *View[E]) Last() E {
+	return (*Query[E])(&w.v).Last()
+}
+
+// LastOrDefault returns the last element of the View,
+// or def if the View is empty.
+func (w *View[E]) LastOrDefault(def E) E {
+	return (*Query[E])(&w.v).LastOrDefault(def)
+}
+
+// Lazy returns a deferred pipeline over the elements of the View.
+func (w *View[E]) Lazy() *Lazy[E] {
+	return NewLazy(w.v)
+}
+
+// Len returns the number of elements in the View.
+func (w *View[E]) Len() int {
+	return len(w.v)
+}
+
+// Reverse returns a new View with the elements in reverse order.
+//
+// The receiver is not modified.
+func (w *View[E]) Reverse() *View[E] {
+	v := slices.Clone(w.v)
+	slices.Reverse(v)
+	return &View[E]{v: v}
+}
+
+// Skip returns a new View without the first n elements.
+//
+// The result shares the elements of the receiver. As with Query.Skip,
+// the count is clamped to the bounds of the View.
+func (w *View[E]) Skip(n int) *View[E] {
+	v := w.v
+	return &View[E]{v: *(*Query[E])(&v).Skip(n)}
+}
+
+// Sort returns a new View with the elements sorted by the less function.
+//
+// The receiver is not modified. A nil less returns the receiver.
+func (w *View[E]) Sort(le func(E, E) bool) *View[E] {
+	if len(w.v) < 1 || le == nil {
+		return w
+	}
+	v := slices.Clone(w.v)
+	sort.Slice(v, func(i, j int) bool {
+		return le(v[i], v[j])
+	})
+	return &View[E]{v: v}
+}
+
+// String returns a string representation of the View.
+func (w *View[E]) String() string {
+	return fmt.Sprintf("%v", w.v)
+}
+
+// Take returns a new View with the first n elements.
+//
+// The result shares the elements of the receiver. As with Query.Take,
+// the count is clamped to the bounds of the View.
+func (w *View[E]) Take(n int) *View[E] {
+	v := w.v
+	return &View[E]{v: slices.Clip(*(*Query[E])(&v).Take(n))}
+}
+
+// ToQuery returns a new Query with a copy of the elements of the View.
+func (w *View[E]) ToQuery() *Query[E] {
+	return NewQuery(w.ToSlice())
+}
+
+// ToSlice returns a copy of the elements of the View.
+func (w *View[E]) ToSlice() []E {
+	return append(make([]E, 0, len(w.v)), w.v...)
+}
+
+// TryFirst returns the first element of the View.
+//
+// The error wraps ErrEmpty if the View is empty.
+func (w *View[E]) TryFirst() (E, error) {
+	return (*Query[E])(&w.v).TryFirst()
+}
+
+// TryLast returns the last element of the View.
+//
+// The error wraps ErrEmpty if the View is empty.
+func (w *View[E]) TryLast() (E, error) {
+	return (*Query[E])(&w.v).TryLast()
+}
+
+// Where returns a new View with the elements that satisfy f.
+//
+// If every element satisfies f the receiver is returned, otherwise the
+// kept elements are copied. As with Query.Where, a nil f yields an empty
+// View.
+func (w *View[E]) Where(f func(E) bool) *View[E] {
+	if f == nil {
+		return &View[E]{}
+	}
+	for i, e := range w.v {
+		if f(e) {
+			continue
+		}
+		// Copy on the first element that is dropped.
+		v := append(make([]E, 0, len(w.v)-1), w.v[:i]...)
+		for _, e := range w.v[i+1:] {
+			if f(e) {
+				v = append(v, e)
+			}
+		}
+		return &View[E]{v: v}
+	}
+	return w
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Clone(t *testing.T) {
+	q := &Query[int]{3, 1, 2}
+	c := q.Clone()
+	c.Sort(func(e1, e2 int) bool {
+		return e1 < e2
+	})
+	if want := (&Query[int]{3, 1, 2}); !reflect.DeepEqual(q, want) {
+		t.Errorf("source modified to %v, want %v", q, want)
+	}
+	if want := (&Query[int]{1, 2, 3}); !reflect.DeepEqual(c, want) {
+		t.Errorf("Query.Clone() = %v, want %v", c, want)
+	}
+}
+
+func TestView_Immutable(t *testing.T) {
+	v := []int{5, 4, 3, 2, 1}
+	w := NewView(v)
+	tests := []struct {
+		name string
+		f    func() *View[int]
+		want []int
+	}{
+		{
+			name: "each",
+			f: func() *View[int] {
+				return w.Each(func(e int) int { return e * 2 })
+			},
+			want: []int{10, 8, 6, 4, 2},
+		},
+		{
+			name: "sort",
+			f: func() *View[int] {
+				return w.Sort(func(e1, e2 int) bool { return e1 < e2 })
+			},
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name: "reverse",
+			f:    w.Reverse,
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name: "where",
+			f: func() *View[int] {
+				return w.Where(isEven)
+			},
+			want: []int{4, 2},
+		},
+		{
+			name: "nil where",
+			f: func() *View[int] {
+				return w.Where(nil)
+			},
+			want: []int{},
+		},
+		{
+			name: "skip",
+			f: func() *View[int] {
+				return w.Skip(2)
+			},
+			want: []int{3, 2, 1},
+		},
+		{
+			name: "take",
+			f: func() *View[int] {
+				return w.Take(2)
+			},
+			want: []int{5, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f().ToSlice(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("View.%s() = %v, want %v", tt.name, got, tt.want)
+			}
+			if want := []int{5, 4, 3, 2, 1}; !reflect.DeepEqual(v, want) {
+				t.Errorf("source modified to %v, want %v", v, want)
+			}
+			if want := []int{5, 4, 3, 2, 1}; !reflect.DeepEqual(w.ToSlice(), want) {
+				t.Errorf("view modified to %v, want %v", w, want)
+			}
+		})
+	}
+}
+
+func TestView_CopyOnWrite(t *testing.T) {
+	v := []int{2, 4, 6}
+	w := NewView(v)
+	if got := w.Where(isEven); got != w {
+		t.Errorf("View.Where() copied although no element was dropped")
+	}
+	if got := w.Sort(nil); got != w {
+		t.Errorf("View.Sort(nil) copied the view")
+	}
+	take := w.Take(2)
+	if &take.v[0] != &v[0] {
+		t.Errorf("View.Take() copied the elements")
+	}
+	// Appending to a shared view must not overwrite the source.
+	_ = append(take.ToSlice(), 100)
+	_ = append(take.v, 100)
+	if v[2] != 6 {
+		t.Errorf("append through View.Take() overwrote source element, got %v", v[2])
+	}
+}
+
+func TestView_ToQuery(t *testing.T) {
+	v := []int{1, 2, 3}
+	q := NewView(v).ToQuery()
+	q.Each(func(e int) int {
+		return 0
+	})
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(v, want) {
+		t.Errorf("source modified to %v, want %v", v, want)
+	}
+}
+
+func TestView_Reads(t *testing.T) {
+	w := NewQuery([]int{1, 2, 3, 4}).View()
+	if got := w.Len(); got != 4 {
+		t.Errorf("View.Len() = %v, want %v", got, 4)
+	}
+	if got := w.At(1); got != 2 {
+		t.Errorf("View.At() = %v, want %v", got, 2)
+	}
+	if got := w.First(); got != 1 {
+		t.Errorf("View.First() = %v, want %v", got, 1)
+	}
+	if got := w.Last(); got != 4 {
+		t.Errorf("View.Last() = %v, want %v", got, 4)
+	}
+	if got := w.Count(isEven); got != 2 {
+		t.Errorf("View.Count() = %v, want %v", got, 2)
+	}
+	if got := w.Index(isEven); got != 1 {
+		t.Errorf("View.Index() = %v, want %v", got, 1)
+	}
+	if got := w.Fold(0, func(a, b int) int { return a + b }); got != 10 {
+		t.Errorf("View.Fold() = %v, want %v", got, 10)
+	}
+	if got := w.String(); got != "[1 2 3 4]" {
+		t.Errorf("View.String() = %v, want %v", got, "[1 2 3 4]")
+	}
+	if got := w.Lazy().Where(isEven).Count(nil); got != 2 {
+		t.Errorf("View.Lazy().Count() = %v, want %v", got, 2)
+	}
+}
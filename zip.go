@@ -0,0 +1,214 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// A Pair holds two values, such as the elements of two queries
+// combined by Zip.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// NewPair returns a Pair of a and b. It can be passed to Zip to
+// combine the elements of two queries into pairs.
+func NewPair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{First: a, Second: b}
+}
+
+// String returns a string representation of the Pair.
+func (p Pair[A, B]) String() string {
+	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+}
+
+// A ZipMode tells Zip how to handle queries of different lengths.
+type ZipMode int
+
+const (
+	// ZipShortest stops at the end of the shorter Query.
+	ZipShortest ZipMode = iota
+	// ZipLongest continues to the end of the longer Query, passing
+	// the zero value for the missing elements of the shorter one.
+	ZipLongest
+)
+
+// Zip combines the elements of a and b at the same index with f.
+//
+// The mode tells how many elements the result has if the queries have
+// different lengths, see ZipMode. The source queries are not modified.
+// A nil f yields an empty Query.
+func Zip[A, B, R any](a *Query[A], b *Query[B], mode ZipMode, f func(A, B) R) *Query[R] {
+	if f == nil {
+		return &Query[R]{}
+	}
+	n := min(len(*a), len(*b))
+	if mode == ZipLongest {
+		n = max(len(*a), len(*b))
+	}
+	result := Query[R](make([]R, n))
+	for i := range result {
+		var x A
+		var y B
+		if i < len(*a) {
+			x = (*a)[i]
+		}
+		if i < len(*b) {
+			y = (*b)[i]
+		}
+		result[i] = f(x, y)
+	}
+	return &result
+}
+
+// Unzip splits a Query of pairs into a Query of their first values and
+// a Query of their second values.
+//
+// The source Query is not modified.
+func Unzip[A, B any](q *Query[Pair[A, B]]) (*Query[A], *Query[B]) {
+	first := Query[A](make([]A, len(*q)))
+	second := Query[B](make([]B, len(*q)))
+	for i, p := range *q {
+		first[i], second[i] = p.First, p.Second
+	}
+	return &first, &second
+}
+
+// Concat returns a new Query with the elements of every Query in qs,
+// one Query after the other.
+//
+// The source queries are not modified. Nil queries are skipped.
+func Concat[E any](qs ...*Query[E]) *Query[E] {
+	n := 0
+	for _, q := range qs {
+		if q != nil {
+			n += len(*q)
+		}
+	}
+	result := Query[E](make([]E, 0, n))
+	for _, q := range qs {
+		if q != nil {
+			result = append(result, *q...)
+		}
+	}
+	return &result
+}
+
+// Append adds the elements v to the end of the Query.
+//
+// Returns: a pointer to the modified Query.
+//
+// Like the built-in append, Append reuses the backing array of the
+// Query if it has room for v, which may be shared with the slice the
+// Query was created from.
+func (q *Query[E]) Append(v ...E) *Query[E] {
+	*q = append(*q, v...)
+	q.touch()
+	return q
+}
+
+// Prepend adds the elements v to the start of the Query.
+//
+// Returns: a pointer to the modified Query.
+func (q *Query[E]) Prepend(v ...E) *Query[E] {
+	result := make([]E, 0, len(v)+len(*q))
+	*q = append(append(result, v...), *q...)
+	q.touch()
+	return q
+}
+
+// Interleave returns a new Query that takes the elements of the queries
+// in qs in turn: the first element of every Query, then the second
+// element of every Query, and so on. Queries that run out of elements
+// are skipped.
+//
+// The source queries are not modified. Nil queries are skipped.
+func Interleave[E any](qs ...*Query[E]) *Query[E] {
+	n, longest := 0, 0
+	for _, q := range qs {
+		if q != nil {
+			n += len(*q)
+			longest = max(longest, len(*q))
+		}
+	}
+	result := Query[E](make([]E, 0, n))
+	for i := 0; i < longest; i++ {
+		for _, q := range qs {
+			if q != nil && i < len(*q) {
+				result = append(result, (*q)[i])
+			}
+		}
+	}
+	return &result
+}
+
+// MergeSorted merges queries that are each sorted by c into a new
+// sorted Query, in O(n log k) time for n elements in k queries.
+//
+// c returns a negative number if a comes before b, a positive number if
+// it comes after b, and zero if their order does not matter, as for
+// slices.SortFunc. The merge is stable: elements that compare equal
+// keep their order within their Query, and come in the order of their
+// queries in qs. The result is not sorted if a Query is not.
+//
+// The source queries are not modified. Nil queries are skipped, and a
+// nil c yields an empty Query.
+func MergeSorted[E any](c func(a, b E) int, qs ...*Query[E]) *Query[E] {
+	if c == nil {
+		return &Query[E]{}
+	}
+	h := &merge[E]{c: c}
+	n := 0
+	for k, q := range qs {
+		if q != nil && len(*q) > 0 {
+			h.heads = append(h.heads, cursor[E]{v: *q, k: k})
+			n += len(*q)
+		}
+	}
+	heap.Init(h)
+	result := Query[E](make([]E, 0, n))
+	for h.Len() > 0 {
+		top := &h.heads[0]
+		result = append(result, top.v[top.i])
+		if top.i++; top.i < len(top.v) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return &result
+}
+
+// cursor is the position i in the elements v of the Query k of a merge.
+type cursor[E any] struct {
+	v    []E
+	i, k int
+}
+
+// merge is a min-heap of cursors by their current elements, and by the
+// order of their queries for equal elements.
+type merge[E any] struct {
+	c     func(a, b E) int
+	heads []cursor[E]
+}
+
+func (h *merge[E]) Len() int { return len(h.heads) }
+func (h *merge[E]) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if r := h.c(a.v[a.i], b.v[b.i]); r != 0 {
+		return r < 0
+	}
+	return a.k < b.k
+}
+func (h *merge[E]) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *merge[E]) Push(x any)    { h.heads = append(h.heads, x.(cursor[E])) }
+func (h *merge[E]) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Daniel Mundt. All rights reserved.
+// Use of this source code is governed by a
+// MIT license that can be found in the LICENSE file.
+
+package sliceql
+
+import (
+	"cmp"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func Test_Zip(t *testing.T) {
+	a := &Query[int]{1, 2, 3}
+	b := &Query[string]{"a", "b"}
+	tests := []struct {
+		name string
+		mode ZipMode
+		f    func(int, string) Pair[int, string]
+		want []Pair[int, string]
+	}{
+		{
+			name: "nil function",
+			mode: ZipShortest,
+			want: []Pair[int, string]{},
+		},
+		{
+			name: "shortest",
+			mode: ZipShortest,
+			f:    NewPair[int, string],
+			want: []Pair[int, string]{{1, "a"}, {2, "b"}},
+		},
+		{
+			name: "longest",
+			mode: ZipLongest,
+			f:    NewPair[int, string],
+			want: []Pair[int, string]{{1, "a"}, {2, "b"}, {3, ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Zip(a, b, tt.mode, tt.f); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Zip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := Zip(&Query[int]{}, b, ZipShortest, NewPair[int, string]); len(*got) != 0 {
+		t.Errorf("Zip() with empty Query = %v, want []", got)
+	}
+}
+
+func Test_Unzip(t *testing.T) {
+	q := &Query[Pair[int, string]]{{1, "a"}, {2, "b"}}
+	first, second := Unzip(q)
+	if !reflect.DeepEqual(first.ToSlice(), []int{1, 2}) || !reflect.DeepEqual(second.ToSlice(), []string{"a", "b"}) {
+		t.Errorf("Unzip() = %v, %v, want [1 2], [a b]", first, second)
+	}
+	first, second = Unzip(&Query[Pair[int, string]]{})
+	if len(*first) != 0 || len(*second) != 0 {
+		t.Errorf("Unzip() on empty Query = %v, %v, want [], []", first, second)
+	}
+	if got := (Pair[int, string]{1, "a"}).String(); got != "(1, a)" {
+		t.Errorf("Pair.String() = %q, want %q", got, "(1, a)")
+	}
+}
+
+func Test_Concat(t *testing.T) {
+	tests := []struct {
+		name string
+		qs   []*Query[int]
+		want []int
+	}{
+		{
+			name: "no queries",
+			want: []int{},
+		},
+		{
+			name: "nil and empty queries",
+			qs:   []*Query[int]{nil, {}},
+			want: []int{},
+		},
+		{
+			name: "several queries",
+			qs:   []*Query[int]{{1, 2}, nil, {3}, {}, {4, 5}},
+			want: []int{1, 2, 3, 4, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Concat(tt.qs...); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Concat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_AppendPrepend(t *testing.T) {
+	q := &Query[int]{2, 3}
+	if got := q.Append(4, 5).Prepend(0, 1); got != q || !reflect.DeepEqual(q.ToSlice(), []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Query.Append().Prepend() = %v, want [0 1 2 3 4 5]", got)
+	}
+	empty := &Query[int]{}
+	if got := empty.Prepend().Append(); *got == nil || len(*got) != 0 {
+		t.Errorf("Query.Prepend().Append() = %#v, want an empty Query", got)
+	}
+
+	ix := NewHashIndex(q, isEven)
+	defer ix.Close()
+	q.Append(6)
+	q.Prepend(-2)
+	if got := ix.Count(true); got != 5 {
+		t.Errorf("HashIndex.Count() after Append() and Prepend() = %d, want 5", got)
+	}
+}
+
+func Test_Interleave(t *testing.T) {
+	tests := []struct {
+		name string
+		qs   []*Query[int]
+		want []int
+	}{
+		{
+			name: "no queries",
+			want: []int{},
+		},
+		{
+			name: "equal lengths",
+			qs:   []*Query[int]{{1, 4}, {2, 5}, {3, 6}},
+			want: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name: "different lengths",
+			qs:   []*Query[int]{{1}, nil, {2, 4, 6}, {3, 5}},
+			want: []int{1, 2, 3, 4, 5, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interleave(tt.qs...); !reflect.DeepEqual(got.ToSlice(), tt.want) {
+				t.Errorf("Interleave() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MergeSorted(t *testing.T) {
+	if got := MergeSorted[int](nil, &Query[int]{1}); len(*got) != 0 {
+		t.Errorf("MergeSorted(nil) = %v, want []", got)
+	}
+	if got := MergeSorted(cmp.Compare[int]); *got == nil || len(*got) != 0 {
+		t.Errorf("MergeSorted() without queries = %#v, want an empty Query", got)
+	}
+
+	// Merging sorted queries yields the stable sort of their
+	// concatenation. Elements are compared by key only, so the shard
+	// and position of equal keys tell whether the merge is stable.
+	type item struct{ key, shard, pos int }
+	byKey := func(a, b item) int {
+		return cmp.Compare(a.key, b.key)
+	}
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 200; i++ {
+		qs := make([]*Query[item], r.Intn(6))
+		for k := range qs {
+			if r.Intn(5) == 0 {
+				continue
+			}
+			q := Create(r.Intn(20), func(int) item {
+				return item{key: r.Intn(10), shard: k}
+			})
+			slices.SortStableFunc(*q, byKey)
+			for j := range *q {
+				(*q)[j].pos = j
+			}
+			qs[k] = q
+		}
+		want := Concat(qs...).ToSlice()
+		slices.SortStableFunc(want, byKey)
+		if got := MergeSorted(byKey, qs...); !reflect.DeepEqual(got.ToSlice(), want) {
+			t.Fatalf("MergeSorted() = %v, want %v", got, want)
+		}
+	}
+}